@@ -0,0 +1,57 @@
+// Command oas3-gen generates the typed Go code of an OpenAPI 3 Specification.
+//
+// Usage:
+//
+//	oas3-gen -spec openapi.yaml -package api -out server.gen.go
+//	oas3-gen -spec openapi.yaml -package client -server=false -client -out client.gen.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/SVilgelm/oas3-server/pkg/gen"
+	"github.com/SVilgelm/oas3-server/pkg/oas3"
+)
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("oas3-gen", flag.ContinueOnError)
+	spec := flags.String("spec", "", "the OpenAPI 3 Specification file or URL")
+	pkg := flags.String("package", "api", "the package name of the generated code")
+	out := flags.String("out", "", "the output file, stdout if empty")
+	srv := flags.Bool("server", true, "generate the server interface and the Register adapter")
+	client := flags.Bool("client", false, "generate the client")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *spec == "" {
+		return fmt.Errorf("-spec is required")
+	}
+	model, err := oas3.Load(*spec, oas3.WithExternalRefs())
+	if err != nil {
+		return err
+	}
+	src, err := gen.Generate(model, gen.Options{
+		Package: *pkg,
+		Server:  *srv,
+		Client:  *client,
+	})
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return ioutil.WriteFile(*out, src, 0644)
+}
+
+func main() {
+	log.SetFlags(0)
+	if err := run(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,219 @@
+// Command oas3-server serves an OpenAPI 3 Specification with the static files and the mocks of the operations.
+//
+// Usage:
+//
+//	oas3-server [serve] [-config config.yaml] [-mock=false] [config flags]
+//	oas3-server validate-spec [-config config.yaml] [spec ...]
+//	oas3-server routes [-config config.yaml] [-format text|json] [config flags]
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/SVilgelm/oas3-server/pkg/config"
+	"github.com/SVilgelm/oas3-server/pkg/oas3"
+	"github.com/SVilgelm/oas3-server/pkg/server"
+)
+
+const usage = `Usage: oas3-server [command] [flags]
+
+Commands:
+  serve          serve the specification, the default command
+  validate-spec  validate the specifications of the arguments or of the config
+  routes         list the routes of the operations
+
+Run 'oas3-server <command> -h' for the flags of the command.
+`
+
+// defaultConfig is used if it exists and -config is not set
+const defaultConfig = "config.yaml"
+
+func newFlagSet(name string, stderr io.Writer) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet("oas3-server "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fileName := fs.String("config", "", "the config file, "+defaultConfig+" if it exists")
+	return fs, fileName
+}
+
+func loadConfig(fs *flag.FlagSet, fileName string) (*config.Config, error) {
+	if fileName == "" {
+		if _, err := os.Stat(defaultConfig); err == nil {
+			fileName = defaultConfig
+		}
+	}
+	return config.LoadWithFlags(fileName, fs)
+}
+
+func serve(args []string, stderr io.Writer) error {
+	fs, fileName := newFlagSet("serve", stderr)
+	mock := fs.Bool("mock", true, "mock the operations without handlers")
+	config.RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := loadConfig(fs, *fileName)
+	if err != nil {
+		return err
+	}
+	srv, err := newServer(cfg, *mock)
+	if err != nil {
+		return err
+	}
+	return srv.Serve()
+}
+
+// newServer creates the server with the mocks and the static files of the config
+func newServer(cfg *config.Config, mock bool) (*server.Server, error) {
+	srv, err := server.NewServer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if mock {
+		srv.MockUnbound()
+	}
+	if cfg.Static != "" && srv.API("").Mapper().ByID("static") == nil {
+		srv.ServeStatic()
+	}
+	return srv, nil
+}
+
+func validateSpec(args []string, stdout, stderr io.Writer) error {
+	fs, fileName := newFlagSet("validate-spec", stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	specs := fs.Args()
+	if len(specs) == 0 {
+		cfg, err := loadConfig(fs, *fileName)
+		if err != nil {
+			return err
+		}
+		models := map[string]*oas3Model{"": {cfg.OAS3, cfg.Specs, cfg.Model}}
+		for _, api := range cfg.APIs {
+			models[api.Name] = &oas3Model{api.OAS3, api.Specs, api.Model}
+		}
+		return checkModels(models, stdout)
+	}
+	failed := false
+	for _, spec := range specs {
+		model, err := oas3.Load(spec, oas3.WithExternalRefs())
+		if err != nil {
+			fmt.Fprintf(stdout, "%s: %s\n", spec, err.Error())
+			failed = true
+			continue
+		}
+		if !checkModel(spec, model, stdout) {
+			failed = true
+		}
+	}
+	if failed {
+		return errors.New("invalid specification")
+	}
+	return nil
+}
+
+type oas3Model struct {
+	main  string
+	specs []string
+	model *openapi3.Swagger
+}
+
+func checkModels(models map[string]*oas3Model, stdout io.Writer) error {
+	names := make([]string, 0, len(models))
+	for name := range models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	failed := false
+	for _, name := range names {
+		m := models[name]
+		if m.model == nil {
+			continue
+		}
+		title := strings.Join(append([]string{m.main}, m.specs...), ", ")
+		title = strings.TrimPrefix(title, ", ")
+		if name != "" {
+			title = name + " (" + title + ")"
+		}
+		if !checkModel(title, m.model, stdout) {
+			failed = true
+		}
+	}
+	if failed {
+		return errors.New("invalid specification")
+	}
+	return nil
+}
+
+// checkModel reports the conflicting and the ambiguous paths of the valid model
+func checkModel(title string, model *openapi3.Swagger, stdout io.Writer) bool {
+	warnings, err := oas3.CheckPaths(model)
+	for _, warning := range warnings {
+		fmt.Fprintf(stdout, "%s: warning: %s\n", title, warning)
+	}
+	if err != nil {
+		fmt.Fprintf(stdout, "%s: %s\n", title, err.Error())
+		return false
+	}
+	fmt.Fprintf(stdout, "%s: OK\n", title)
+	return true
+}
+
+func routes(args []string, stdout, stderr io.Writer) error {
+	fs, fileName := newFlagSet("routes", stderr)
+	format := fs.String("format", "text", "the output format: text or json")
+	config.RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+	cfg, err := loadConfig(fs, *fileName)
+	if err != nil {
+		return err
+	}
+	srv, err := server.NewServer(cfg)
+	if err != nil {
+		return err
+	}
+	return oas3.WriteRoutes(stdout, *format, srv.Routes())
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	command := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+	switch command {
+	case "serve":
+		return serve(args, stderr)
+	case "validate-spec":
+		return validateSpec(args, stdout, stderr)
+	case "routes":
+		return routes(args, stdout, stderr)
+	case "help":
+		fmt.Fprint(stdout, usage)
+		return nil
+	}
+	fmt.Fprint(stderr, usage)
+	return fmt.Errorf("unknown command '%s'", command)
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if err != flag.ErrHelp {
+			log.Println(err)
+		}
+		os.Exit(1)
+	}
+}
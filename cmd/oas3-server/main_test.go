@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SVilgelm/oas3-server/pkg/config"
+	"github.com/SVilgelm/oas3-server/pkg/oas3"
+)
+
+func TestValidateSpec(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"validate-spec", "testdata/api.yaml", "testdata/conflict.yaml"}, &stdout, &stderr)
+	assert.EqualError(t, err, "invalid specification")
+	assert.Contains(t, stdout.String(), "testdata/api.yaml: OK\n")
+	assert.Contains(t, stdout.String(), "testdata/conflict.yaml: conflicting paths:")
+
+	stdout.Reset()
+	err = run([]string{"validate-spec", "-config", "testdata/config.yaml"}, &stdout, &stderr)
+	assert.NoError(t, err)
+	assert.Equal(t, "testdata/api.yaml: OK\n", stdout.String())
+}
+
+func TestRoutes(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"routes", "-config", "testdata/config.yaml"}, &stdout, &stderr)
+	assert.NoError(t, err)
+	assert.Equal(t, `API  METHOD  PATH            OPERATION    BOUND  WILDCARD  PARAMS
+     GET     /api/openapi    oas3.model   true   false
+     GET     /api/pets/{id}  pets.get     false  false     path:id*
+     DELETE  /api/pets/{id}  pets.delete  false  false     path:id*
+     GET     /api/pets       pets.list    false  false
+`, stdout.String())
+
+	stdout.Reset()
+	err = run([]string{"routes", "-config", "testdata/config.yaml", "-format", "json"}, &stdout, &stderr)
+	assert.NoError(t, err)
+	var routes []oas3.RouteInfo
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &routes))
+	assert.Len(t, routes, 4)
+	assert.Equal(t, oas3.RouteInfo{
+		OperationID: "pets.get",
+		Method:      http.MethodGet,
+		Path:        "/api/pets/{id}",
+		Params:      []oas3.ParamInfo{{In: "path", Name: "id", Required: true, Type: "integer"}},
+	}, routes[1])
+
+	err = run([]string{"routes", "-format", "xml"}, &stdout, &stderr)
+	assert.EqualError(t, err, "unknown format 'xml'")
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"unknown"}, &stdout, &stderr)
+	assert.EqualError(t, err, "unknown command 'unknown'")
+	assert.Contains(t, stderr.String(), "Usage: oas3-server")
+}
+
+func TestServeMocks(t *testing.T) {
+	t.Parallel()
+	cfg, err := config.Load("testdata/config.yaml")
+	assert.NoError(t, err)
+	cfg.Address = "127.0.0.1:0"
+	srv, err := newServer(cfg, true)
+	assert.NoError(t, err)
+	assert.NoError(t, srv.Start())
+	defer func() {
+		assert.NoError(t, srv.Shutdown())
+	}()
+
+	do := func(method, path string) (int, string) {
+		req, err := http.NewRequest(method, srv.URL()+path, nil)
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	status, body := do(http.MethodGet, "api/pets")
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `[{"id":1,"name":"string","born":"1970-01-01"}]`, body)
+	status, body = do(http.MethodGet, "api/pets/1")
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"id":7,"name":"Rex"}`, body)
+	status, _ = do(http.MethodGet, "api/pets/abc")
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, body = do(http.MethodDelete, "api/pets/1")
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Empty(t, body)
+	status, body = do(http.MethodGet, "api/openapi")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"title":"Pets"`)
+	status, body = do(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "<h1>index</h1>\n", body)
+	status, _ = do(http.MethodGet, "healthz")
+	assert.Equal(t, http.StatusOK, status)
+}
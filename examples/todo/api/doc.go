@@ -0,0 +1,4 @@
+// Package api is generated from the Todo specification
+package api
+
+//go:generate go run ../../../cmd/oas3-gen -spec ../todo.yaml -package api -out server.gen.go
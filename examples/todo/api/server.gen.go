@@ -0,0 +1,571 @@
+// Code generated by oas3-gen. DO NOT EDIT.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/SVilgelm/oas3-server/pkg/oas3"
+	"github.com/SVilgelm/oas3-server/pkg/server"
+)
+
+// Error is generated from the schema 'Error'
+type Error struct {
+	Message string `json:"message"`
+}
+
+// NewTodo is a todo to create
+type NewTodo struct {
+	Tags  []string `json:"tags,omitempty"`
+	Title string   `json:"title"`
+}
+
+// Todo is a todo item
+type Todo struct {
+	Done  bool              `json:"done"`
+	ID    int64             `json:"id"`
+	Meta  map[string]string `json:"meta,omitempty"`
+	Tags  []string          `json:"tags,omitempty"`
+	Title string            `json:"title"`
+}
+
+// TodoUpdate is a change of a todo
+type TodoUpdate struct {
+	Done  *bool   `json:"done,omitempty"`
+	Title *string `json:"title,omitempty"`
+}
+
+// TodosListRequest is the request of the operation 'todos.list'
+type TodosListRequest struct {
+	Limit      *int32
+	Tags       []string
+	XRequestID *string
+	// HTTPRequest is the original request, set by the server
+	HTTPRequest *http.Request
+}
+
+// TodosCreateRequest is the request of the operation 'todos.create'
+type TodosCreateRequest struct {
+	Body *NewTodo
+	// HTTPRequest is the original request, set by the server
+	HTTPRequest *http.Request
+}
+
+// TodosGetRequest is the request of the operation 'todos.get'
+type TodosGetRequest struct {
+	ID int64
+	// HTTPRequest is the original request, set by the server
+	HTTPRequest *http.Request
+}
+
+// TodosUpdateRequest is the request of the operation 'todos.update'
+type TodosUpdateRequest struct {
+	ID   int64
+	Body *TodoUpdate
+	// HTTPRequest is the original request, set by the server
+	HTTPRequest *http.Request
+}
+
+// TodosDeleteRequest is the request of the operation 'todos.delete'
+type TodosDeleteRequest struct {
+	ID int64
+	// HTTPRequest is the original request, set by the server
+	HTTPRequest *http.Request
+}
+
+// TodosTitleRequest is the request of the operation 'todos.title'
+type TodosTitleRequest struct {
+	ID int64
+	// HTTPRequest is the original request, set by the server
+	HTTPRequest *http.Request
+}
+
+// TodosListResponse is the response of the operation 'todos.list'
+type TodosListResponse struct {
+	// Status is 200 if not set
+	Status int
+	Header http.Header
+	Body   []Todo
+}
+
+// TodosCreateResponse is the response of the operation 'todos.create'
+type TodosCreateResponse struct {
+	// Status is 201 if not set
+	Status int
+	Header http.Header
+	Body   *Todo
+}
+
+// TodosGetResponse is the response of the operation 'todos.get'
+type TodosGetResponse struct {
+	// Status is 200 if not set
+	Status int
+	Header http.Header
+	Body   *Todo
+}
+
+// TodosUpdateResponse is the response of the operation 'todos.update'
+type TodosUpdateResponse struct {
+	// Status is 200 if not set
+	Status int
+	Header http.Header
+	Body   *Todo
+}
+
+// TodosDeleteResponse is the response of the operation 'todos.delete'
+type TodosDeleteResponse struct {
+	// Status is 204 if not set
+	Status int
+	Header http.Header
+}
+
+// TodosTitleResponse is the response of the operation 'todos.title'
+type TodosTitleResponse struct {
+	// Status is 200 if not set
+	Status int
+	Header http.Header
+	// ContentType is 'text/plain' if not set
+	ContentType string
+	Body        []byte
+}
+
+// Interface is implemented by the handlers of the operations
+type Interface interface {
+	// TodosList handles the operation 'todos.list': List the todos
+	TodosList(ctx context.Context, req *TodosListRequest) (*TodosListResponse, error)
+	// TodosCreate handles the operation 'todos.create': Create a todo
+	TodosCreate(ctx context.Context, req *TodosCreateRequest) (*TodosCreateResponse, error)
+	// TodosGet handles the operation 'todos.get': Get a todo
+	TodosGet(ctx context.Context, req *TodosGetRequest) (*TodosGetResponse, error)
+	// TodosUpdate handles the operation 'todos.update': Update a todo by a form
+	TodosUpdate(ctx context.Context, req *TodosUpdateRequest) (*TodosUpdateResponse, error)
+	// TodosDelete handles the operation 'todos.delete': Delete a todo
+	TodosDelete(ctx context.Context, req *TodosDeleteRequest) (*TodosDeleteResponse, error)
+	// TodosTitle handles the operation 'todos.title': Get the title of a todo as text
+	TodosTitle(ctx context.Context, req *TodosTitleRequest) (*TodosTitleResponse, error)
+}
+
+// StatusError is returned by the handlers to respond with the status code
+type StatusError struct {
+	Status  int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
+// Register links the handlers of the implementation with the operations of the server
+func Register(srv *server.Server, impl Interface) error {
+	if err := srv.Handle("todos.list", handleTodosList(impl)); err != nil {
+		return err
+	}
+	if err := srv.Handle("todos.create", handleTodosCreate(impl)); err != nil {
+		return err
+	}
+	if err := srv.Handle("todos.get", handleTodosGet(impl)); err != nil {
+		return err
+	}
+	if err := srv.Handle("todos.update", handleTodosUpdate(impl)); err != nil {
+		return err
+	}
+	if err := srv.Handle("todos.delete", handleTodosDelete(impl)); err != nil {
+		return err
+	}
+	if err := srv.Handle("todos.title", handleTodosTitle(impl)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func handleTodosList(impl Interface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := TodosListRequest{HTTPRequest: r}
+		if values, ok := queryValues(r, "limit"); ok {
+			var v int32
+			if err := parseValues(values, "", "", &v); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid query parameter 'limit': " + err.Error()})
+				return
+			}
+			req.Limit = &v
+		}
+		if values, ok := queryValues(r, "tags"); ok {
+			if err := parseValues(values, "", "", &req.Tags); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid query parameter 'tags': " + err.Error()})
+				return
+			}
+		}
+		if values, ok := headerValues(r, "X-Request-ID"); ok {
+			var v string
+			if err := parseValues(values, "", ",", &v); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid header parameter 'X-Request-ID': " + err.Error()})
+				return
+			}
+			req.XRequestID = &v
+		}
+		resp, err := impl.TodosList(r.Context(), &req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if resp == nil {
+			resp = &TodosListResponse{}
+		}
+		if resp.Status == 0 {
+			resp.Status = 200
+		}
+		for name, values := range resp.Header {
+			w.Header()[name] = values
+		}
+		if resp.Body != nil {
+			writeJSON(w, resp.Status, resp.Body)
+			return
+		}
+		w.WriteHeader(resp.Status)
+	}
+}
+
+func handleTodosCreate(impl Interface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := TodosCreateRequest{HTTPRequest: r}
+		var body NewTodo
+		if ok, err := decodeBody(r, &body); err != nil {
+			if _, tooLarge := err.(*oas3.Error); !tooLarge {
+				err = &StatusError{http.StatusBadRequest, "invalid body: " + err.Error()}
+			}
+			writeError(w, err)
+			return
+		} else if ok {
+			req.Body = &body
+		} else {
+			writeError(w, &StatusError{http.StatusBadRequest, "body is required"})
+			return
+		}
+		resp, err := impl.TodosCreate(r.Context(), &req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if resp == nil {
+			resp = &TodosCreateResponse{}
+		}
+		if resp.Status == 0 {
+			resp.Status = 201
+		}
+		for name, values := range resp.Header {
+			w.Header()[name] = values
+		}
+		if resp.Body != nil {
+			writeJSON(w, resp.Status, resp.Body)
+			return
+		}
+		w.WriteHeader(resp.Status)
+	}
+}
+
+func handleTodosGet(impl Interface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := TodosGetRequest{HTTPRequest: r}
+		if values, ok := pathValues(r, "id"); ok {
+			if err := parseValues(values, "", ",", &req.ID); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid path parameter 'id': " + err.Error()})
+				return
+			}
+		} else {
+			writeError(w, &StatusError{http.StatusBadRequest, "path parameter 'id' is required"})
+			return
+		}
+		resp, err := impl.TodosGet(r.Context(), &req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if resp == nil {
+			resp = &TodosGetResponse{}
+		}
+		if resp.Status == 0 {
+			resp.Status = 200
+		}
+		for name, values := range resp.Header {
+			w.Header()[name] = values
+		}
+		if resp.Body != nil {
+			writeJSON(w, resp.Status, resp.Body)
+			return
+		}
+		w.WriteHeader(resp.Status)
+	}
+}
+
+func handleTodosUpdate(impl Interface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := TodosUpdateRequest{HTTPRequest: r}
+		if values, ok := pathValues(r, "id"); ok {
+			if err := parseValues(values, "", ",", &req.ID); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid path parameter 'id': " + err.Error()})
+				return
+			}
+		} else {
+			writeError(w, &StatusError{http.StatusBadRequest, "path parameter 'id' is required"})
+			return
+		}
+		var body TodoUpdate
+		if ok, err := decodeBody(r, &body); err != nil {
+			if _, tooLarge := err.(*oas3.Error); !tooLarge {
+				err = &StatusError{http.StatusBadRequest, "invalid body: " + err.Error()}
+			}
+			writeError(w, err)
+			return
+		} else if ok {
+			req.Body = &body
+		} else {
+			writeError(w, &StatusError{http.StatusBadRequest, "body is required"})
+			return
+		}
+		resp, err := impl.TodosUpdate(r.Context(), &req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if resp == nil {
+			resp = &TodosUpdateResponse{}
+		}
+		if resp.Status == 0 {
+			resp.Status = 200
+		}
+		for name, values := range resp.Header {
+			w.Header()[name] = values
+		}
+		if resp.Body != nil {
+			writeJSON(w, resp.Status, resp.Body)
+			return
+		}
+		w.WriteHeader(resp.Status)
+	}
+}
+
+func handleTodosDelete(impl Interface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := TodosDeleteRequest{HTTPRequest: r}
+		if values, ok := pathValues(r, "id"); ok {
+			if err := parseValues(values, "", ",", &req.ID); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid path parameter 'id': " + err.Error()})
+				return
+			}
+		} else {
+			writeError(w, &StatusError{http.StatusBadRequest, "path parameter 'id' is required"})
+			return
+		}
+		resp, err := impl.TodosDelete(r.Context(), &req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if resp == nil {
+			resp = &TodosDeleteResponse{}
+		}
+		if resp.Status == 0 {
+			resp.Status = 204
+		}
+		for name, values := range resp.Header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(resp.Status)
+	}
+}
+
+func handleTodosTitle(impl Interface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := TodosTitleRequest{HTTPRequest: r}
+		if values, ok := pathValues(r, "id"); ok {
+			if err := parseValues(values, "", ",", &req.ID); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid path parameter 'id': " + err.Error()})
+				return
+			}
+		} else {
+			writeError(w, &StatusError{http.StatusBadRequest, "path parameter 'id' is required"})
+			return
+		}
+		resp, err := impl.TodosTitle(r.Context(), &req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if resp == nil {
+			resp = &TodosTitleResponse{}
+		}
+		if resp.Status == 0 {
+			resp.Status = 200
+		}
+		for name, values := range resp.Header {
+			w.Header()[name] = values
+		}
+		if resp.ContentType == "" {
+			resp.ContentType = "text/plain"
+		}
+		w.Header().Set("Content-Type", resp.ContentType)
+		w.WriteHeader(resp.Status)
+		_, _ = w.Write(resp.Body)
+	}
+}
+
+func pathValues(r *http.Request, name string) ([]string, bool) {
+	value, ok := mux.Vars(r)[name]
+	return []string{value}, ok
+}
+
+func queryValues(r *http.Request, name string) ([]string, bool) {
+	values, ok := r.URL.Query()[name]
+	return values, ok
+}
+
+func headerValues(r *http.Request, name string) ([]string, bool) {
+	values, ok := r.Header[http.CanonicalHeaderKey(name)]
+	return values, ok
+}
+
+func cookieValues(r *http.Request, name string) ([]string, bool) {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return nil, false
+	}
+	return []string{cookie.Value}, true
+}
+
+// parseValues converts the values of a parameter to the target, the repeated values are the items
+// of the array without a separator, otherwise they are joined by comma
+func parseValues(values []string, prefix, separator string, target interface{}) error {
+	rv := reflect.ValueOf(target).Elem()
+	if separator != "" || rv.Kind() != reflect.Slice {
+		return parseValue(strings.Join(values, ","), prefix, separator, target)
+	}
+	slice := reflect.MakeSlice(rv.Type(), len(values), len(values))
+	for i, value := range values {
+		if err := parseValue(value, prefix, separator, slice.Index(i).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	rv.Set(slice)
+	return nil
+}
+
+// parseValue converts the value of a parameter to the target,
+// the prefix of the style is removed and the arrays are split by the separator
+func parseValue(value, prefix, separator string, target interface{}) error {
+	if !strings.HasPrefix(value, prefix) {
+		return fmt.Errorf("the value must start with '%s'", prefix)
+	}
+	value = value[len(prefix):]
+	var err error
+	switch t := target.(type) {
+	case *string:
+		*t = value
+	case *int64:
+		*t, err = strconv.ParseInt(value, 10, 64)
+	case *int32:
+		var v int64
+		v, err = strconv.ParseInt(value, 10, 32)
+		*t = int32(v)
+	case *float64:
+		*t, err = strconv.ParseFloat(value, 64)
+	case *float32:
+		var v float64
+		v, err = strconv.ParseFloat(value, 32)
+		*t = float32(v)
+	case *bool:
+		*t, err = strconv.ParseBool(value)
+	default:
+		rv := reflect.ValueOf(target).Elem()
+		if rv.Kind() != reflect.Slice {
+			return json.Unmarshal([]byte(value), target)
+		}
+		parts := strings.Split(value, separator)
+		slice := reflect.MakeSlice(rv.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := parseValue(part, "", separator, slice.Index(i).Addr().Interface()); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+	}
+	return err
+}
+
+// decodeBody decodes the JSON or form body, false means the body is empty
+func decodeBody(r *http.Request, target interface{}) (bool, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err != nil {
+			return false, err
+		}
+		if len(r.PostForm) == 0 {
+			return false, nil
+		}
+		return true, decodeForm(r.PostForm, target)
+	}
+	err := json.NewDecoder(r.Body).Decode(target)
+	if err == io.EOF {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// decodeForm sets the fields of the target struct by the form values named as the JSON fields
+func decodeForm(values url.Values, target interface{}) error {
+	rv := reflect.ValueOf(target).Elem()
+	if rv.Kind() != reflect.Struct {
+		return errors.New("the form body must be an object")
+	}
+	for i := 0; i < rv.NumField(); i++ {
+		name := strings.Split(rv.Type().Field(i).Tag.Get("json"), ",")[0]
+		formValues, ok := values[name]
+		if !ok {
+			continue
+		}
+		field := rv.Field(i)
+		if field.Kind() == reflect.Ptr {
+			field.Set(reflect.New(field.Type().Elem()))
+			field = field.Elem()
+		}
+		if err := parseValue(strings.Join(formValues, ","), "", ",", field.Addr().Interface()); err != nil {
+			return fmt.Errorf("invalid field '%s': %v", name, err)
+		}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+// writeError responds by the status of the error, the errors of the server like the body over the limit
+// are written as they are
+func writeError(w http.ResponseWriter, err error) {
+	var serverErr *oas3.Error
+	if errors.As(err, &serverErr) {
+		oas3.WriteError(w, serverErr)
+		return
+	}
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		statusErr = &StatusError{http.StatusInternalServerError, err.Error()}
+	}
+	writeJSON(w, statusErr.Status, map[string]string{"message": statusErr.Message})
+}
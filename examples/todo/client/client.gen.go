@@ -0,0 +1,488 @@
+// Code generated by oas3-gen. DO NOT EDIT.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Error is generated from the schema 'Error'
+type Error struct {
+	Message string `json:"message"`
+}
+
+// NewTodo is a todo to create
+type NewTodo struct {
+	Tags  []string `json:"tags,omitempty"`
+	Title string   `json:"title"`
+}
+
+// Todo is a todo item
+type Todo struct {
+	Done  bool              `json:"done"`
+	ID    int64             `json:"id"`
+	Meta  map[string]string `json:"meta,omitempty"`
+	Tags  []string          `json:"tags,omitempty"`
+	Title string            `json:"title"`
+}
+
+// TodoUpdate is a change of a todo
+type TodoUpdate struct {
+	Done  *bool   `json:"done,omitempty"`
+	Title *string `json:"title,omitempty"`
+}
+
+// TodosListRequest is the request of the operation 'todos.list'
+type TodosListRequest struct {
+	Limit      *int32
+	Tags       []string
+	XRequestID *string
+	// HTTPRequest is the original request, set by the server
+	HTTPRequest *http.Request
+}
+
+// TodosCreateRequest is the request of the operation 'todos.create'
+type TodosCreateRequest struct {
+	Body *NewTodo
+	// HTTPRequest is the original request, set by the server
+	HTTPRequest *http.Request
+}
+
+// TodosGetRequest is the request of the operation 'todos.get'
+type TodosGetRequest struct {
+	ID int64
+	// HTTPRequest is the original request, set by the server
+	HTTPRequest *http.Request
+}
+
+// TodosUpdateRequest is the request of the operation 'todos.update'
+type TodosUpdateRequest struct {
+	ID   int64
+	Body *TodoUpdate
+	// HTTPRequest is the original request, set by the server
+	HTTPRequest *http.Request
+}
+
+// TodosDeleteRequest is the request of the operation 'todos.delete'
+type TodosDeleteRequest struct {
+	ID int64
+	// HTTPRequest is the original request, set by the server
+	HTTPRequest *http.Request
+}
+
+// TodosTitleRequest is the request of the operation 'todos.title'
+type TodosTitleRequest struct {
+	ID int64
+	// HTTPRequest is the original request, set by the server
+	HTTPRequest *http.Request
+}
+
+// TodosListResult is the result of the operation 'todos.list'
+type TodosListResult struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the raw body of the response
+	Body []byte
+	// JSON200 is the body of the 200 response
+	JSON200 []Todo
+}
+
+// TodosCreateResult is the result of the operation 'todos.create'
+type TodosCreateResult struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the raw body of the response
+	Body []byte
+	// JSON201 is the body of the 201 response
+	JSON201 *Todo
+	// JSONDefault is the body of the default response
+	JSONDefault *Error
+}
+
+// TodosGetResult is the result of the operation 'todos.get'
+type TodosGetResult struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the raw body of the response
+	Body []byte
+	// JSON200 is the body of the 200 response
+	JSON200 *Todo
+	// JSON404 is the body of the 404 response
+	JSON404 *Error
+}
+
+// TodosUpdateResult is the result of the operation 'todos.update'
+type TodosUpdateResult struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the raw body of the response
+	Body []byte
+	// JSON200 is the body of the 200 response
+	JSON200 *Todo
+	// JSON404 is the body of the 404 response
+	JSON404 *Error
+}
+
+// TodosDeleteResult is the result of the operation 'todos.delete'
+type TodosDeleteResult struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the raw body of the response
+	Body []byte
+	// JSON404 is the body of the 404 response
+	JSON404 *Error
+}
+
+// TodosTitleResult is the result of the operation 'todos.title'
+type TodosTitleResult struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the raw body of the response
+	Body []byte
+}
+
+// Client calls the operations of the API
+type Client struct {
+	// BaseURL is the URL of the server including the base path
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a Client, http.DefaultClient is used if httpClient is nil
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: httpClient,
+	}
+}
+
+// TodosList calls the operation 'todos.list': List the todos
+func (c *Client) TodosList(ctx context.Context, req *TodosListRequest) (*TodosListResult, error) {
+	if req == nil {
+		req = &TodosListRequest{}
+	}
+	path := "/todos"
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []*http.Cookie
+	if req.Limit != nil {
+		addQuery(query, "form", true, "limit", req.Limit)
+	}
+	if req.Tags != nil {
+		addQuery(query, "form", true, "tags", req.Tags)
+	}
+	if req.XRequestID != nil {
+		header.Set("X-Request-ID", strings.Join(formatValues(req.XRequestID), ","))
+	}
+	var body []byte
+	resp, data, err := c.do(ctx, "GET", path, query, header, cookies, body)
+	if err != nil {
+		return nil, err
+	}
+	result := TodosListResult{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       data,
+	}
+	switch resp.StatusCode {
+	case 200:
+		err = decodeResult(data, &result.JSON200)
+	}
+	return &result, err
+}
+
+// TodosCreate calls the operation 'todos.create': Create a todo
+func (c *Client) TodosCreate(ctx context.Context, req *TodosCreateRequest) (*TodosCreateResult, error) {
+	if req == nil {
+		req = &TodosCreateRequest{}
+	}
+	path := "/todos"
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []*http.Cookie
+	var body []byte
+	if req.Body != nil {
+		data, err := encodeBody("application/json", req.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+		header.Set("Content-Type", "application/json")
+	}
+	resp, data, err := c.do(ctx, "POST", path, query, header, cookies, body)
+	if err != nil {
+		return nil, err
+	}
+	result := TodosCreateResult{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       data,
+	}
+	switch resp.StatusCode {
+	case 201:
+		err = decodeResult(data, &result.JSON201)
+	default:
+		err = decodeResult(data, &result.JSONDefault)
+	}
+	return &result, err
+}
+
+// TodosGet calls the operation 'todos.get': Get a todo
+func (c *Client) TodosGet(ctx context.Context, req *TodosGetRequest) (*TodosGetResult, error) {
+	if req == nil {
+		req = &TodosGetRequest{}
+	}
+	path := "/todos/{id}"
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []*http.Cookie
+	path = strings.Replace(path, "{id}", formatPath("simple", false, "id", req.ID), 1)
+	var body []byte
+	resp, data, err := c.do(ctx, "GET", path, query, header, cookies, body)
+	if err != nil {
+		return nil, err
+	}
+	result := TodosGetResult{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       data,
+	}
+	switch resp.StatusCode {
+	case 200:
+		err = decodeResult(data, &result.JSON200)
+	case 404:
+		err = decodeResult(data, &result.JSON404)
+	}
+	return &result, err
+}
+
+// TodosUpdate calls the operation 'todos.update': Update a todo by a form
+func (c *Client) TodosUpdate(ctx context.Context, req *TodosUpdateRequest) (*TodosUpdateResult, error) {
+	if req == nil {
+		req = &TodosUpdateRequest{}
+	}
+	path := "/todos/{id}"
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []*http.Cookie
+	path = strings.Replace(path, "{id}", formatPath("simple", false, "id", req.ID), 1)
+	var body []byte
+	if req.Body != nil {
+		data, err := encodeBody("application/x-www-form-urlencoded", req.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+		header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	resp, data, err := c.do(ctx, "PATCH", path, query, header, cookies, body)
+	if err != nil {
+		return nil, err
+	}
+	result := TodosUpdateResult{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       data,
+	}
+	switch resp.StatusCode {
+	case 200:
+		err = decodeResult(data, &result.JSON200)
+	case 404:
+		err = decodeResult(data, &result.JSON404)
+	}
+	return &result, err
+}
+
+// TodosDelete calls the operation 'todos.delete': Delete a todo
+func (c *Client) TodosDelete(ctx context.Context, req *TodosDeleteRequest) (*TodosDeleteResult, error) {
+	if req == nil {
+		req = &TodosDeleteRequest{}
+	}
+	path := "/todos/{id}"
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []*http.Cookie
+	path = strings.Replace(path, "{id}", formatPath("simple", false, "id", req.ID), 1)
+	var body []byte
+	resp, data, err := c.do(ctx, "DELETE", path, query, header, cookies, body)
+	if err != nil {
+		return nil, err
+	}
+	result := TodosDeleteResult{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       data,
+	}
+	switch resp.StatusCode {
+	case 404:
+		err = decodeResult(data, &result.JSON404)
+	}
+	return &result, err
+}
+
+// TodosTitle calls the operation 'todos.title': Get the title of a todo as text
+func (c *Client) TodosTitle(ctx context.Context, req *TodosTitleRequest) (*TodosTitleResult, error) {
+	if req == nil {
+		req = &TodosTitleRequest{}
+	}
+	path := "/todos/{id}/title"
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []*http.Cookie
+	path = strings.Replace(path, "{id}", formatPath("simple", false, "id", req.ID), 1)
+	var body []byte
+	resp, data, err := c.do(ctx, "GET", path, query, header, cookies, body)
+	if err != nil {
+		return nil, err
+	}
+	result := TodosTitleResult{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       data,
+	}
+	return &result, err
+}
+
+func (c *Client) do(
+	ctx context.Context,
+	method, path string,
+	query url.Values,
+	header http.Header,
+	cookies []*http.Cookie,
+	body []byte,
+) (*http.Response, []byte, error) {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, data, nil
+}
+
+// formatValues converts a scalar or an array to the strings
+func formatValues(value interface{}) []string {
+	rv := reflect.Indirect(reflect.ValueOf(value))
+	if rv.Kind() != reflect.Slice {
+		return []string{formatValue(rv)}
+	}
+	values := make([]string, rv.Len())
+	for i := range values {
+		values[i] = formatValue(reflect.Indirect(rv.Index(i)))
+	}
+	return values
+}
+
+func formatValue(rv reflect.Value) string {
+	switch rv.Kind() {
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+// formatPath serializes the path parameter with the simple, label or matrix style
+func formatPath(style string, explode bool, name string, value interface{}) string {
+	values := formatValues(value)
+	for i, v := range values {
+		values[i] = url.PathEscape(v)
+	}
+	switch style {
+	case "label":
+		if explode {
+			return "." + strings.Join(values, ".")
+		}
+		return "." + strings.Join(values, ",")
+	case "matrix":
+		if explode {
+			return ";" + name + "=" + strings.Join(values, ";"+name+"=")
+		}
+		return ";" + name + "=" + strings.Join(values, ",")
+	}
+	return strings.Join(values, ",")
+}
+
+// addQuery serializes the query parameter with the form, spaceDelimited or pipeDelimited style
+func addQuery(query url.Values, style string, explode bool, name string, value interface{}) {
+	values := formatValues(value)
+	switch {
+	case explode:
+		query[name] = values
+	case style == "spaceDelimited":
+		query.Set(name, strings.Join(values, " "))
+	case style == "pipeDelimited":
+		query.Set(name, strings.Join(values, "|"))
+	default:
+		query.Set(name, strings.Join(values, ","))
+	}
+}
+
+// encodeBody encodes the body by the media type
+func encodeBody(contentType string, body interface{}) ([]byte, error) {
+	if contentType != "application/x-www-form-urlencoded" {
+		return json.Marshal(body)
+	}
+	form, err := encodeForm(body)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(form.Encode()), nil
+}
+
+// encodeForm converts the fields of the struct to the form values named as the JSON fields
+func encodeForm(body interface{}) (url.Values, error) {
+	rv := reflect.Indirect(reflect.ValueOf(body))
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.New("the form body must be an object")
+	}
+	form := url.Values{}
+	for i := 0; i < rv.NumField(); i++ {
+		name := strings.Split(rv.Type().Field(i).Tag.Get("json"), ",")[0]
+		field := rv.Field(i)
+		if (field.Kind() == reflect.Ptr || field.Kind() == reflect.Slice) && field.IsNil() {
+			continue
+		}
+		form[name] = formatValues(field.Interface())
+	}
+	return form, nil
+}
+
+func decodeResult(data []byte, target interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, target)
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatPath(t *testing.T) {
+	t.Parallel()
+	values := []int64{3, 4}
+	for _, tc := range []struct {
+		style    string
+		explode  bool
+		value    interface{}
+		expected string
+	}{
+		{"simple", false, "a b", "a%20b"},
+		{"simple", false, values, "3,4"},
+		{"label", false, values, ".3,4"},
+		{"label", true, values, ".3.4"},
+		{"matrix", false, values, ";id=3,4"},
+		{"matrix", true, values, ";id=3;id=4"},
+		{"simple", false, 1.5, "1.5"},
+	} {
+		assert.Equal(t, tc.expected, formatPath(tc.style, tc.explode, "id", tc.value), tc.style)
+	}
+}
+
+func TestAddQuery(t *testing.T) {
+	t.Parallel()
+	values := []string{"a", "b"}
+	for _, tc := range []struct {
+		style    string
+		explode  bool
+		expected string
+	}{
+		{"form", true, "q=a&q=b"},
+		{"form", false, "q=a%2Cb"},
+		{"spaceDelimited", false, "q=a+b"},
+		{"pipeDelimited", false, "q=a%7Cb"},
+		{"spaceDelimited", true, "q=a&q=b"},
+		{"pipeDelimited", true, "q=a&q=b"},
+	} {
+		query := url.Values{}
+		addQuery(query, tc.style, tc.explode, "q", values)
+		assert.Equal(t, tc.expected, query.Encode(), tc.style)
+	}
+}
+
+func TestEncodeBody(t *testing.T) {
+	t.Parallel()
+	title := "a"
+	data, err := encodeBody("application/x-www-form-urlencoded", &TodoUpdate{Title: &title})
+	assert.NoError(t, err)
+	assert.Equal(t, "title=a", string(data))
+	_, err = encodeBody("application/x-www-form-urlencoded", []string{})
+	assert.Error(t, err)
+	data, err = encodeBody("application/json", &TodoUpdate{Title: &title})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"title":"a"}`, string(data))
+}
@@ -0,0 +1,4 @@
+// Package client is the client generated from the Todo specification
+package client
+
+//go:generate go run ../../../cmd/oas3-gen -spec ../todo.yaml -package client -server=false -client -out client.gen.go
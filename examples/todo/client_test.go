@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SVilgelm/oas3-server/examples/todo/client"
+	"github.com/SVilgelm/oas3-server/pkg/config"
+	"github.com/SVilgelm/oas3-server/pkg/oas3"
+)
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+	model, err := oas3.Load("todo.yaml")
+	assert.NoError(t, err)
+	srv, err := initServer(&config.Config{
+		Address:  "127.0.0.1:0",
+		Model:    model,
+		Validate: config.Validation{Request: true, Response: true},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, srv.Start())
+	defer func() {
+		assert.NoError(t, srv.Shutdown())
+	}()
+
+	ctx := context.Background()
+	c := client.NewClient(srv.URL(), nil)
+	created, err := c.TodosCreate(ctx, &client.TodosCreateRequest{
+		Body: &client.NewTodo{Title: "first", Tags: []string{"a", "b"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, created.StatusCode)
+	assert.Equal(t, &client.Todo{ID: 1, Title: "first", Tags: []string{"a", "b"}}, created.JSON201)
+	_, err = c.TodosCreate(ctx, &client.TodosCreateRequest{Body: &client.NewTodo{Title: "second"}})
+	assert.NoError(t, err)
+
+	invalid, err := c.TodosCreate(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, invalid.StatusCode)
+
+	limit := int32(1)
+	list, err := c.TodosList(ctx, &client.TodosListRequest{Limit: &limit})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, list.StatusCode)
+	assert.Len(t, list.JSON200, 1)
+	list, err = c.TodosList(ctx, &client.TodosListRequest{Tags: []string{"a", "b"}})
+	assert.NoError(t, err)
+	assert.Len(t, list.JSON200, 1)
+	assert.Equal(t, "first", list.JSON200[0].Title)
+
+	title, done := "renamed", true
+	updated, err := c.TodosUpdate(ctx, &client.TodosUpdateRequest{
+		ID:   2,
+		Body: &client.TodoUpdate{Title: &title, Done: &done},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, updated.StatusCode)
+	assert.Equal(t, &client.Todo{ID: 2, Title: "renamed", Done: true}, updated.JSON200)
+
+	text, err := c.TodosTitle(ctx, &client.TodosTitleRequest{ID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "renamed", string(text.Body))
+
+	deleted, err := c.TodosDelete(ctx, &client.TodosDeleteRequest{ID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, deleted.StatusCode)
+	missing, err := c.TodosGet(ctx, &client.TodosGetRequest{ID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, missing.StatusCode)
+	assert.Nil(t, missing.JSON200)
+	assert.Equal(t, &client.Error{Message: "todo not found"}, missing.JSON404)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/SVilgelm/oas3-server/examples/todo/api"
+	"github.com/SVilgelm/oas3-server/pkg/config"
+	"github.com/SVilgelm/oas3-server/pkg/server"
+)
+
+// Store keeps the todos in memory and implements the generated api.Interface
+type Store struct {
+	mu    sync.Mutex
+	next  int64
+	todos map[int64]*api.Todo
+}
+
+// NewStore creates an empty Store
+func NewStore() *Store {
+	return &Store{todos: make(map[int64]*api.Todo)}
+}
+
+func (s *Store) find(id int64) (*api.Todo, error) {
+	todo, ok := s.todos[id]
+	if !ok {
+		return nil, &api.StatusError{Status: http.StatusNotFound, Message: "todo not found"}
+	}
+	return todo, nil
+}
+
+// TodosList returns the todos ordered by id
+func (s *Store) TodosList(ctx context.Context, req *api.TodosListRequest) (*api.TodosListResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	todos := []api.Todo{}
+	for _, todo := range s.todos {
+		if len(req.Tags) > 0 && !hasTags(todo, req.Tags) {
+			continue
+		}
+		todos = append(todos, *todo)
+	}
+	sort.Slice(todos, func(i, j int) bool {
+		return todos[i].ID < todos[j].ID
+	})
+	if req.Limit != nil && int(*req.Limit) < len(todos) {
+		todos = todos[:*req.Limit]
+	}
+	return &api.TodosListResponse{Body: todos}, nil
+}
+
+func hasTags(todo *api.Todo, tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, t := range todo.Tags {
+			if t == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// TodosCreate adds a new todo
+func (s *Store) TodosCreate(ctx context.Context, req *api.TodosCreateRequest) (*api.TodosCreateResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	todo := api.Todo{
+		ID:    s.next,
+		Title: req.Body.Title,
+		Tags:  req.Body.Tags,
+	}
+	s.todos[todo.ID] = &todo
+	return &api.TodosCreateResponse{Body: &todo}, nil
+}
+
+// TodosGet returns the todo by its id
+func (s *Store) TodosGet(ctx context.Context, req *api.TodosGetRequest) (*api.TodosGetResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	todo, err := s.find(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	res := *todo
+	return &api.TodosGetResponse{Body: &res}, nil
+}
+
+// TodosUpdate changes the title or the state of the todo
+func (s *Store) TodosUpdate(ctx context.Context, req *api.TodosUpdateRequest) (*api.TodosUpdateResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	todo, err := s.find(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Body.Title != nil {
+		todo.Title = *req.Body.Title
+	}
+	if req.Body.Done != nil {
+		todo.Done = *req.Body.Done
+	}
+	res := *todo
+	return &api.TodosUpdateResponse{Body: &res}, nil
+}
+
+// TodosDelete removes the todo
+func (s *Store) TodosDelete(ctx context.Context, req *api.TodosDeleteRequest) (*api.TodosDeleteResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.find(req.ID); err != nil {
+		return nil, err
+	}
+	delete(s.todos, req.ID)
+	return nil, nil
+}
+
+// TodosTitle returns the title of the todo as a text
+func (s *Store) TodosTitle(ctx context.Context, req *api.TodosTitleRequest) (*api.TodosTitleResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	todo, err := s.find(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &api.TodosTitleResponse{Body: []byte(todo.Title)}, nil
+}
+
+func initServer(cfg *config.Config) (*server.Server, error) {
+	srv, err := server.NewServer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.Register(srv, NewStore()); err != nil {
+		return nil, err
+	}
+	return srv, nil
+}
+
+func main() {
+	cfg, err := config.Load("config.yaml")
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := initServer(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := srv.Serve(); err != nil {
+		log.Fatal(err)
+	}
+}
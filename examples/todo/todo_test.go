@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SVilgelm/oas3-server/examples/todo/api"
+	"github.com/SVilgelm/oas3-server/pkg/config"
+	"github.com/SVilgelm/oas3-server/pkg/oas3"
+)
+
+func TestTodo(t *testing.T) {
+	t.Parallel()
+	model, err := oas3.Load("todo.yaml")
+	assert.NoError(t, err)
+	srv, err := initServer(&config.Config{
+		Address:     "127.0.0.1:0",
+		Model:       model,
+		Validate:    config.Validation{Request: true, Response: true},
+		MaxBodySize: 256,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, srv.Start())
+	defer func() {
+		assert.NoError(t, srv.Shutdown())
+	}()
+
+	do := func(method, path, body string) (int, string) {
+		req, err := http.NewRequest(method, srv.URL()+path, strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	status, body := do(http.MethodPost, "todos", `{"title":"first","tags":["a"]}`)
+	assert.Equal(t, http.StatusCreated, status)
+	var todo api.Todo
+	assert.NoError(t, json.Unmarshal([]byte(body), &todo))
+	assert.Equal(t, api.Todo{ID: 1, Title: "first", Tags: []string{"a"}}, todo)
+	status, _ = do(http.MethodPost, "todos", `{"title":"second"}`)
+	assert.Equal(t, http.StatusCreated, status)
+	status, body = do(http.MethodPost, "todos", ``)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.JSONEq(t, `{"message":"body is required"}`, body)
+
+	// the length of the body is unknown, the limit is checked by reading
+	req, err := http.NewRequest(http.MethodPost, srv.URL()+"todos", struct{ io.Reader }{
+		strings.NewReader(`{"title":"` + strings.Repeat("a", 256) + `"}`),
+	})
+	assert.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	data, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	assert.JSONEq(t, `{"status":413,"code":"body_too_large","message":"the request body exceeds 256 bytes","operation":"todos.create"}`, string(data))
+
+	status, body = do(http.MethodGet, "todos?limit=1", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `[{"id":1,"title":"first","done":false,"tags":["a"]}]`, body)
+	status, body = do(http.MethodGet, "todos?tags=a", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `[{"id":1,"title":"first","done":false,"tags":["a"]}]`, body)
+	status, body = do(http.MethodGet, "todos", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"second"`)
+
+	status, body = do(http.MethodGet, "todos/2/title", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "second", body)
+
+	status, _ = do(http.MethodDelete, "todos/2", "")
+	assert.Equal(t, http.StatusNoContent, status)
+	status, body = do(http.MethodGet, "todos/2", "")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.JSONEq(t, `{"message":"todo not found"}`, body)
+}
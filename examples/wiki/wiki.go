@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"html/template"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -175,6 +177,10 @@ func initServer() (*server.Server, error) {
 	_ = srv.HandleFunc("wiki.edit", editHandler)
 	_ = srv.HandleFunc("wiki.save", saveHandler)
 	_ = srv.HandleFunc("wiki.view", viewHandler)
+	srv.AddHealthCheck("data", func(ctx context.Context) error {
+		_, err := os.Stat(dataFolder)
+		return err
+	})
 	return srv, nil
 }
 
@@ -50,7 +50,7 @@ func setupTests() (func(), error) {
 	if err != nil {
 		return tearDown, err
 	}
-	baseURL = "http://" + srv.Config.Address + "/"
+	baseURL = srv.URL()
 	tearDowns = append(tearDowns, func() {
 		_ = srv.Shutdown()
 	})
@@ -122,3 +122,20 @@ func TestList(t *testing.T) {
 	t.Logf("List of articles: %+v", res)
 	assert.Contains(t, res, "testArticle")
 }
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+	for path, checks := range map[string]int{"healthz": 0, "readyz": 1} {
+		resp, err := http.Get(baseURL + path)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("content-type"), "application/json")
+		data, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.NoError(t, err)
+		var report map[string]interface{}
+		assert.NoError(t, json.Unmarshal(data, &report))
+		assert.Equal(t, "ok", report["status"])
+		assert.Len(t, report["checks"], checks, path)
+	}
+}
@@ -8,4 +8,5 @@ require (
 	github.com/gorilla/mux v1.7.3
 	github.com/qri-io/jsonschema v0.1.1
 	github.com/stretchr/testify v1.4.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -1,72 +1,233 @@
 package config
 
 import (
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 
-	"github.com/ghodss/yaml"
-
 	"github.com/SVilgelm/oas3-server/pkg/oas3"
 )
 
 // Config is a main cofing
 type Config struct {
-	OAS3     string     `json:"oas3,omitempty"`
-	Address  string     `json:"address,omitempty"`
-	TLS      TLS        `json:"tls,omitempty"`
-	Static   string     `json:"static,omitempty"`
-	Validate Validation `json:"validate,omitempty"`
+	// OAS3 is a file or an URL of the specification, it can refer to the components of the sibling files
+	OAS3 string `json:"oas3,omitempty"`
+	// Specs are the files or the URLs of the specifications merged into the OAS3 one
+	Specs []string `json:"specs,omitempty"`
+	// BasePath overrides the path of the first server URL of the specification, "/" mounts the operations at the root
+	BasePath  string      `json:"base_path,omitempty"`
+	APIs      []API       `json:"apis,omitempty"`
+	Address   string      `json:"address,omitempty"`
+	TLS       TLS         `json:"tls,omitempty"`
+	Listeners []Listener  `json:"listeners,omitempty"`
+	Static    string      `json:"static,omitempty"`
+	Validate  Validation  `json:"validate,omitempty"`
+	Publish   Publishing  `json:"publish,omitempty"`
+	Compress  Compression `json:"compress,omitempty"`
+	// MaxBodySize limits the request bodies in bytes, the x-max-body-size extension overrides it per operation
+	MaxBodySize int64 `json:"max_body_size,omitempty"`
+	// Timeout limits the handling of the requests, the x-timeout extension overrides it per operation
+	Timeout  Duration `json:"timeout,omitempty"`
+	Tracing  Tracing  `json:"tracing,omitempty"`
+	Shutdown Shutdown `json:"shutdown,omitempty"`
 
 	Model *openapi3.Swagger `json:"-,omitempty"`
 }
 
+// API is used for an additional specification served by the same server,
+// it has its own handlers and base path
+type API struct {
+	Name     string   `json:"name,omitempty"`
+	OAS3     string   `json:"oas3,omitempty"`
+	Specs    []string `json:"specs,omitempty"`
+	BasePath string   `json:"base_path,omitempty"`
+
+	Model *openapi3.Swagger `json:"-"`
+}
+
 // TLS is used for tls settings
 type TLS struct {
 	Enabled bool   `json:"enabled,omitempty"`
 	Cert    string `json:"cert,omitempty"`
 	Key     string `json:"key,omitempty"`
+	// ClientCA is a file with PEM encoded CA certificates to verify the client certificates
+	ClientCA string `json:"client_ca,omitempty"`
+	// ClientAuth is one of none, request, verify-if-given, require (the same as require-and-verify),
+	// the certificates are verified by ClientCA except for none and request
+	ClientAuth string `json:"client_auth,omitempty"`
+	// MinVersion is one of 1.0, 1.1, 1.2, 1.3
+	MinVersion   string   `json:"min_version,omitempty"`
+	CipherSuites []string `json:"cipher_suites,omitempty"`
+}
+
+// Listener is used for a listener settings,
+// the listeners override Address and TLS to serve several addresses by the same router
+type Listener struct {
+	Address string `json:"address,omitempty"`
+	// Socket is a path of an unix socket, used instead of Address
+	Socket string `json:"socket,omitempty"`
+	TLS    TLS    `json:"tls,omitempty"`
+	// RedirectHTTPS redirects all requests to the first TLS listener instead of serving them
+	RedirectHTTPS bool `json:"redirect_https,omitempty"`
 }
 
 // Validation is used for Validation settings
 type Validation struct {
-	Request  bool `json:"request,omitempty"`
-	Response bool `json:"response,omitempty"`
+	Request    bool `json:"request,omitempty"`
+	Response   bool `json:"response,omitempty"`
+	PathParams bool `json:"path_params,omitempty"`
+}
+
+// Publishing is used for the settings of the model served by the oas3.model operation
+type Publishing struct {
+	// Internal publishes the operations, the parameters and the properties marked by the x-internal
+	// or x-visibility extensions, they are hidden by default
+	Internal bool `json:"internal,omitempty"`
+}
+
+// Compression is used for the compression of the responses
+type Compression struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// MinSize is the minimal size of the compressed responses in bytes, 1024 by default
+	MinSize int `json:"min_size,omitempty"`
+	// Level is from -2 (Huffman only) to 9 (best compression), the default level if not set
+	Level int `json:"level,omitempty"`
+}
+
+// Tracing is used for tracing settings
+type Tracing struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Output is a file to export the spans, "stdout" by default
+	Output string `json:"output,omitempty"`
+}
+
+// Shutdown is used for graceful shutdown settings
+type Shutdown struct {
+	// Timeout limits the time to finish the active requests and to run the shutdown hooks
+	Timeout Duration `json:"timeout,omitempty"`
+	// Drain is a delay between failing the readiness checks and closing the listeners
+	Drain Duration `json:"drain,omitempty"`
+}
+
+func loadSpec(location string) (*openapi3.Swagger, error) {
+	if oas3.IsURL(location) {
+		return oas3.LoadFromURL(location, oas3.WithExternalRefs())
+	}
+	return oas3.Load(location, oas3.WithExternalRefs())
+}
+
+// loadModel loads the main specification and merges the other specs into it
+func loadModel(main string, specs []string) (*openapi3.Swagger, error) {
+	var locations []string
+	if main != "" {
+		locations = append(locations, main)
+	}
+	locations = append(locations, specs...)
+	models := make([]*openapi3.Swagger, len(locations))
+	for i, location := range locations {
+		model, err := loadSpec(location)
+		if err != nil {
+			return nil, err
+		}
+		models[i] = model
+	}
+	if len(models) == 1 {
+		return models[0], nil
+	}
+	model, err := oas3.Merge(models...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to merge %s: %s", strings.Join(locations, ", "), err.Error())
+	}
+	return model, nil
 }
 
+// init disables TLS without the certificate, loads the models and applies the defaults,
+// the values are validated by the checker before
 func (c *Config) init() error {
 	if c.TLS.Cert == "" || c.TLS.Key == "" {
 		c.TLS.Enabled = false
 	}
-	if c.OAS3 != "" {
-		model, err := oas3.Load(c.OAS3)
+	for i := range c.Listeners {
+		l := &c.Listeners[i]
+		if l.TLS.Cert == "" || l.TLS.Key == "" {
+			l.TLS.Enabled = false
+		}
+	}
+	if c.OAS3 != "" || len(c.Specs) > 0 {
+		model, err := loadModel(c.OAS3, c.Specs)
 		if err != nil {
 			return fmt.Errorf("invalid model: %s", err.Error())
 		}
 		c.Model = model
 	}
+	for i := range c.APIs {
+		api := &c.APIs[i]
+		model, err := loadModel(api.OAS3, api.Specs)
+		if err != nil {
+			return fmt.Errorf("api '%s': invalid model: %s", api.Name, err.Error())
+		}
+		api.Model = model
+	}
 	if c.Address == "" {
 		c.Address = "0.0.0.0:8000"
 	}
+	if c.Shutdown.Timeout <= 0 {
+		c.Shutdown.Timeout = Duration(30 * time.Second)
+	}
+	if c.Tracing.Output == "" {
+		c.Tracing.Output = "stdout"
+	}
 	return nil
 }
 
-// Load loads a config file
+// Load loads a config file and applies the environment variables
 func Load(fileName string) (*Config, error) {
-	data, err := ioutil.ReadFile(fileName)
-	if err != nil {
+	return LoadWithFlags(fileName, nil)
+}
+
+// LoadWithFlags loads a config file and applies the environment variables and the flags,
+// the precedence order is: flags, environment variables, the config file, the defaults.
+// The config file is optional if fileName is empty,
+// ${VAR} and ${VAR:-default} in the config file are replaced by the environment variables.
+func LoadWithFlags(fileName string, fs *flag.FlagSet) (*Config, error) {
+	var cfg Config
+	c := checker{}
+	decoding := false
+	if fileName != "" {
+		data, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+		c.positions, c.problems, err = decodeStrict(interpolate(data), &cfg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal the file '%s'. %s", fileName, err)
+		}
+		decoding = len(c.problems) > 0
+		log.Println("Loaded config file:", fileName)
+	}
+	if err := cfg.ApplyEnv(); err != nil {
 		return nil, err
 	}
-	var cfg Config
-	err = yaml.Unmarshal(data, &cfg)
-	if err != nil {
-		return nil, fmt.Errorf("unable to unmarshal the file '%s'. %s", fileName, err)
+	if fs != nil {
+		if err := cfg.ApplyFlags(fs); err != nil {
+			return nil, err
+		}
 	}
-	log.Println("Loaded config file:", fileName)
-	err = cfg.init()
-	if err != nil {
+	c.validate(&cfg)
+	if len(c.problems) > 0 {
+		c.sort()
+		return nil, &Error{
+			FileName: fileName,
+			Decoding: decoding,
+			Problems: c.problems,
+		}
+	}
+	if err := cfg.init(); err != nil {
 		return nil, err
 	}
 	return &cfg, nil
@@ -1,7 +1,13 @@
 package config
 
 import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -116,3 +122,457 @@ func TestLoad(t *testing.T) {
 	assert.NotNil(t, cfg.Model)
 	assert.Equal(t, "9.9.9", cfg.Model.Info.Version)
 }
+
+func TestConfigInitTracing(t *testing.T) {
+	t.Parallel()
+	cfg := Config{}
+	err := cfg.init()
+	assert.NoError(t, err)
+	assert.Equal(t, "stdout", cfg.Tracing.Output)
+
+	cfg.Tracing.Output = "spans.json"
+	err = cfg.init()
+	assert.NoError(t, err)
+	assert.Equal(t, "spans.json", cfg.Tracing.Output)
+}
+
+func TestConfigInitShutdown(t *testing.T) {
+	t.Parallel()
+	cfg := Config{}
+	err := cfg.init()
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.Shutdown.Timeout.Duration())
+	assert.Zero(t, cfg.Shutdown.Drain)
+
+	cfg.Shutdown.Timeout = Duration(time.Second)
+	err = cfg.init()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, cfg.Shutdown.Timeout.Duration())
+}
+
+func TestDuration(t *testing.T) {
+	t.Parallel()
+	var d Duration
+	assert.NoError(t, json.Unmarshal([]byte(`"1m30s"`), &d))
+	assert.Equal(t, 90*time.Second, d.Duration())
+	assert.NoError(t, json.Unmarshal([]byte(`2.5`), &d))
+	assert.Equal(t, 2500*time.Millisecond, d.Duration())
+	assert.Error(t, json.Unmarshal([]byte(`"forever"`), &d))
+	assert.Error(t, json.Unmarshal([]byte(`true`), &d))
+
+	data, err := json.Marshal(Duration(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, `"1m0s"`, string(data))
+}
+
+func TestConfigInitListeners(t *testing.T) {
+	t.Parallel()
+	cfg := Config{
+		Listeners: []Listener{
+			{Address: ":8080", RedirectHTTPS: true},
+			{Address: ":8443", TLS: TLS{Enabled: true, Cert: "cert"}},
+		},
+	}
+	err := cfg.init()
+	assert.NoError(t, err)
+	assert.False(t, cfg.Listeners[1].TLS.Enabled)
+
+	cfg.Listeners[1].TLS = TLS{Enabled: true, Key: "key", Cert: "cert"}
+	err = cfg.init()
+	assert.NoError(t, err)
+	assert.True(t, cfg.Listeners[1].TLS.Enabled)
+}
+
+func TestConfigInitSpecs(t *testing.T) {
+	t.Parallel()
+	cfg := Config{
+		OAS3:  "testdata/model.yaml",
+		Specs: []string{"testdata/fragment.yaml"},
+	}
+	err := cfg.init()
+	assert.NoError(t, err)
+	assert.Equal(t, "9.9.9", cfg.Model.Info.Version)
+	assert.NotNil(t, cfg.Model.Paths["/fragment"])
+
+	cfg.Model = nil
+	cfg.Specs = append(cfg.Specs, "testdata/fragment.yaml")
+	err = cfg.init()
+	assert.EqualError(t, err, "invalid model: unable to merge testdata/model.yaml, testdata/fragment.yaml, testdata/fragment.yaml: "+
+		"conflicting specifications: path '/fragment' is defined in #2 and #3")
+	assert.Nil(t, cfg.Model)
+}
+
+func TestConfigInitAPIs(t *testing.T) {
+	t.Parallel()
+	cfg := Config{
+		APIs: []API{{Name: "v2", OAS3: "testdata/model.yaml"}},
+	}
+	err := cfg.init()
+	assert.NoError(t, err)
+	assert.Equal(t, "9.9.9", cfg.APIs[0].Model.Info.Version)
+
+	cfg.APIs[0] = API{Name: "v2", OAS3: "fake-file"}
+	err = cfg.init()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "api 'v2': invalid model")
+	assert.Nil(t, cfg.APIs[0].Model)
+}
+
+func TestLoadOverrides(t *testing.T) {
+	envs := map[string]string{
+		"OAS3_TEST_MODEL":              "testdata/model.yaml",
+		"OAS3_SERVER_VALIDATE_REQUEST": "false",
+		"OAS3_SERVER_STATIC":           "testdata",
+		"OAS3_SERVER_TLS_CERT":         "env.crt",
+		"OAS3_SERVER_SHUTDOWN_TIMEOUT": "5s",
+	}
+	for k, v := range envs {
+		assert.NoError(t, os.Setenv(k, v))
+	}
+	defer func() {
+		for k := range envs {
+			_ = os.Unsetenv(k)
+		}
+	}()
+
+	cfg, err := Load("testdata/interpolated.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "testdata/model.yaml", cfg.OAS3)
+	assert.NotNil(t, cfg.Model)
+	assert.Equal(t, "127.0.0.1:9000", cfg.Address)
+	assert.Equal(t, "testdata", cfg.Static)
+	assert.False(t, cfg.Validate.Request)
+	assert.Equal(t, "env.crt", cfg.TLS.Cert)
+	assert.Equal(t, 5*time.Second, cfg.Shutdown.Timeout.Duration())
+
+	assert.NoError(t, os.Setenv("OAS3_SERVER_SHUTDOWN_TIMEOUT", "2.5"))
+	cfg, err = Load("testdata/interpolated.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, 2500*time.Millisecond, cfg.Shutdown.Timeout.Duration())
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	RegisterFlags(fs)
+	assert.NotNil(t, fs.Lookup("tls.cipher_suites"))
+	assert.Nil(t, fs.Lookup("listeners"))
+	err = fs.Parse([]string{"-address", ":8443", "-validate.request", "-tls.cipher_suites", "A, B"})
+	assert.NoError(t, err)
+	cfg, err = LoadWithFlags("testdata/interpolated.yaml", fs)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown cipher suite 'A'")
+
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	RegisterFlags(fs)
+	err = fs.Parse([]string{"-address", ":8443", "-validate.request", "-static", ".", "-timeout", "30"})
+	assert.NoError(t, err)
+	cfg, err = LoadWithFlags("", fs)
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.OAS3)
+	assert.Equal(t, ":8443", cfg.Address)
+	assert.Equal(t, ".", cfg.Static)
+	assert.True(t, cfg.Validate.Request)
+	assert.Equal(t, 30*time.Second, cfg.Timeout.Duration())
+
+	assert.NoError(t, os.Setenv("OAS3_SERVER_TIMEOUT", "forever"))
+	_, err = LoadWithFlags("", nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value of OAS3_SERVER_TIMEOUT")
+	assert.NoError(t, os.Unsetenv("OAS3_SERVER_TIMEOUT"))
+
+	assert.NoError(t, os.Setenv("OAS3_SERVER_VALIDATE_RESPONSE", "maybe"))
+	defer os.Unsetenv("OAS3_SERVER_VALIDATE_RESPONSE")
+	_, err = LoadWithFlags("", nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid value of OAS3_SERVER_VALIDATE_RESPONSE")
+}
+
+func TestLoadProblems(t *testing.T) {
+	t.Parallel()
+	cfg, err := Load("testdata/config with problems.yaml")
+	t.Log(err)
+	assert.Nil(t, cfg)
+	var cfgErr *Error
+	assert.True(t, errors.As(err, &cfgErr))
+	assert.True(t, cfgErr.Decoding)
+	assert.Contains(t, err.Error(), "unable to unmarshal the file 'testdata/config with problems.yaml'. 8 problem(s):")
+	assert.Equal(t, []Problem{
+		{Path: "validte", Line: 3, Column: 1, Message: "unknown field"},
+		{Path: "validate.request", Line: 6, Column: 12, Message: `expected boolean, got string "yes-no"`},
+		{Path: "tls.enabled", Line: 9, Column: 12, Message: "key is required if TLS is enabled"},
+		{Path: "tls.cert", Line: 10, Column: 9, Message: "stat missing.crt: no such file or directory"},
+		{Path: "shutdown.timeout", Line: 12, Column: 12, Message: `invalid duration "forever"`},
+		{Path: "listeners[0].redirect_https", Line: 15, Column: 21, Message: "a TLS listener with an address is required to redirect to HTTPS"},
+		{Path: "listeners[0].tsl", Line: 16, Column: 5, Message: "unknown field"},
+		{Path: "listeners[1]", Line: 17, Column: 5, Message: "address and socket are mutually exclusive"},
+	}, cfgErr.Problems)
+	assert.Contains(t, err.Error(), "testdata/config with problems.yaml:3:1: validte: unknown field")
+}
+
+func TestPositions(t *testing.T) {
+	t.Parallel()
+	p := newPositions([]byte(`
+a: 1
+b:
+  c: |
+    d: not a key
+  e: "x" # comment
+f:
+- g: 1
+  h:
+    - 2
+    - i: 3
+j: 4
+`))
+	assert.Equal(t, map[string]position{
+		"a":           {2, 1},
+		"b":           {3, 1},
+		"b.c":         {4, 3},
+		"b.e":         {6, 3},
+		"f":           {7, 1},
+		"f[0]":        {8, 3},
+		"f[0].g":      {8, 3},
+		"f[0].h":      {9, 3},
+		"f[0].h[0]":   {10, 7},
+		"f[0].h[1]":   {11, 7},
+		"f[0].h[1].i": {11, 7},
+		"j":           {12, 1},
+	}, p.keys)
+	pos, ok := p.find("b.e")
+	assert.True(t, ok)
+	assert.Equal(t, position{6, 6}, pos)
+
+	p = newPositions([]byte(`
+base: &base
+  address: ":8080"
+listeners: [{address: ":8081"}, *base]
+tls: {"cert": a.crt, 'key': b.key}
+static: >-
+  folded
+  text
+api:
+  <<: *base
+  name:
+`))
+	assert.Equal(t, map[string]position{
+		"base":                 {2, 1},
+		"base.address":         {3, 3},
+		"listeners":            {4, 1},
+		"listeners[0]":         {4, 13},
+		"listeners[0].address": {4, 14},
+		"listeners[1]":         {4, 33},
+		"listeners[1].address": {3, 3},
+		"tls":                  {5, 1},
+		"tls.cert":             {5, 7},
+		"tls.key":              {5, 22},
+		"static":               {6, 1},
+		"api":                  {9, 1},
+		"api.address":          {3, 3},
+		"api.name":             {11, 3},
+	}, p.keys)
+	pos, ok = p.find("listeners[0].address")
+	assert.True(t, ok)
+	assert.Equal(t, position{4, 23}, pos)
+	pos, ok = p.find("static")
+	assert.True(t, ok)
+	assert.Equal(t, position{6, 9}, pos)
+	pos, ok = p.find("api.name")
+	assert.True(t, ok)
+	assert.Equal(t, position{11, 3}, pos, "the empty value is found by the key")
+
+	// the problems of the paths missing in the file are reported without the positions
+	_, ok = p.find("timeout")
+	assert.False(t, ok)
+	c := checker{positions: p}
+	c.add("timeout", "must not be negative")
+	assert.Equal(t, []Problem{{Path: "timeout", Message: "must not be negative"}}, c.problems)
+	assert.Equal(t, "timeout: must not be negative", c.problems[0].String())
+
+	p = newPositions([]byte("a: [1"))
+	assert.Empty(t, p.keys)
+}
+
+func TestTLSSettings(t *testing.T) {
+	t.Parallel()
+	settings := TLS{
+		ClientCA:     "ca.pem",
+		MinVersion:   "1.2",
+		CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+	}
+	auth, err := settings.ClientAuthType()
+	assert.NoError(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, auth)
+	version, err := settings.Version()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), version)
+	ids, err := settings.CipherSuiteIDs()
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, ids)
+
+	settings.ClientAuth = "require"
+	auth, err = settings.ClientAuthType()
+	assert.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, auth)
+}
+
+// checkConfig decodes the YAML and validates it like LoadWithFlags
+func checkConfig(t *testing.T, data string) []Problem {
+	var cfg Config
+	positions, problems, err := decodeStrict([]byte(data), &cfg)
+	assert.NoError(t, err)
+	c := checker{positions: positions, problems: problems}
+	c.validate(&cfg)
+	c.sort()
+	return c.problems
+}
+
+func TestConfigCheck(t *testing.T) {
+	t.Parallel()
+	cfg := Config{APIs: []API{{Name: "v2", OAS3: "v2.yaml"}}}
+	assert.NoError(t, cfg.Check())
+
+	cfg = Config{
+		MaxBodySize: -1,
+		APIs:        []API{{OAS3: "v2.yaml"}},
+	}
+	err := cfg.Check()
+	var cfgErr *Error
+	assert.True(t, errors.As(err, &cfgErr))
+	assert.False(t, cfgErr.Decoding)
+	assert.Equal(t, []Problem{
+		{Path: "max_body_size", Message: "must not be negative"},
+		{Path: "apis[0]", Message: "name is required"},
+	}, cfgErr.Problems)
+	assert.EqualError(t, err, "invalid config. 2 problem(s):\n\tmax_body_size: must not be negative\n\tapis[0]: name is required")
+}
+
+func TestCheckerValidate(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name     string
+		data     string
+		problems []Problem
+	}{
+		{
+			name: "valid",
+			data: `
+timeout: 30
+max_body_size: 1024
+compress:
+  level: 9
+tls:
+  client_ca: ca.pem
+  client_auth: require
+  min_version: "1.2"
+listeners:
+  - address: ":8080"
+apis:
+  - name: v2
+    oas3: v2.yaml
+`,
+		},
+		{
+			name: "timeout",
+			data: "timeout: -1s\n",
+			problems: []Problem{
+				{Path: "timeout", Line: 1, Column: 10, Message: "must not be negative"},
+			},
+		},
+		{
+			name: "max_body_size",
+			data: "max_body_size: -1\n",
+			problems: []Problem{
+				{Path: "max_body_size", Line: 1, Column: 16, Message: "must not be negative"},
+			},
+		},
+		{
+			name: "compress",
+			data: "compress:\n  enabled: true\n  level: 10\n",
+			problems: []Problem{
+				{Path: "compress.level", Line: 3, Column: 10, Message: "must be from -2 to 9"},
+			},
+		},
+		{
+			name: "compress huffman only",
+			data: "compress:\n  enabled: true\n  level: -2\n",
+		},
+		{
+			name: "tls",
+			data: `
+tls:
+  client_auth: sometimes
+  min_version: "2.0"
+  cipher_suites:
+    - TLS_NULL
+`,
+			problems: []Problem{
+				{Path: "tls.client_auth", Line: 3, Column: 16, Message: "unknown client_auth 'sometimes'"},
+				{Path: "tls.min_version", Line: 4, Column: 16, Message: "unknown min_version '2.0'"},
+				{Path: "tls.cipher_suites", Line: 5, Column: 3, Message: "unknown cipher suite 'TLS_NULL'"},
+			},
+		},
+		{
+			name: "client_ca",
+			data: `
+tls:
+  client_auth: require-and-verify
+listeners:
+  - address: ":8443"
+    tls:
+      client_ca: ca.pem
+      client_auth: request
+`,
+			problems: []Problem{
+				{Path: "tls.client_auth", Line: 3, Column: 16, Message: "client_ca is required for client_auth 'require-and-verify'"},
+				{Path: "listeners[0].tls.client_auth", Line: 8, Column: 20, Message: "client_ca is not used by client_auth 'request'"},
+			},
+		},
+		{
+			name: "listeners",
+			data: `
+listeners:
+  - address: ":8080"
+    redirect_https: true
+  - redirect_https: false
+`,
+			problems: []Problem{
+				{Path: "listeners[0].redirect_https", Line: 4, Column: 21, Message: "a TLS listener with an address is required to redirect to HTTPS"},
+				{Path: "listeners[1]", Line: 5, Column: 5, Message: "address or socket is required"},
+			},
+		},
+		{
+			name: "listeners redirect to a socket",
+			data: `
+listeners:
+  - address: ":8080"
+    redirect_https: true
+  - socket: /tmp/server.sock
+    tls:
+      enabled: true
+`,
+			problems: []Problem{
+				{Path: "listeners[0].redirect_https", Line: 4, Column: 21, Message: "a TLS listener with an address is required to redirect to HTTPS"},
+				{Path: "listeners[1].tls.enabled", Line: 7, Column: 16, Message: "cert is required if TLS is enabled"},
+				{Path: "listeners[1].tls.enabled", Line: 7, Column: 16, Message: "key is required if TLS is enabled"},
+			},
+		},
+		{
+			name: "apis",
+			data: `
+apis:
+  - name: v2
+    oas3: v2.yaml
+  - name: v2
+    specs:
+      - v3.yaml
+  - oas3: v4.yaml
+  - name: v5
+`,
+			problems: []Problem{
+				{Path: "apis[1].name", Line: 5, Column: 11, Message: "duplicated name 'v2'"},
+				{Path: "apis[2]", Line: 8, Column: 5, Message: "name is required"},
+				{Path: "apis[3]", Line: 9, Column: 5, Message: "oas3 or specs is required"},
+			},
+		},
+	} {
+		assert.Equal(t, test.problems, checkConfig(t, test.data), test.name)
+	}
+}
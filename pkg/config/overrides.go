@@ -0,0 +1,177 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix is a prefix of the environment variables overriding the config
+const EnvPrefix = "OAS3_SERVER_"
+
+var durationType = reflect.TypeOf(Duration(0))
+
+var interpolation = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// interpolate replaces ${VAR} and ${VAR:-default} by the values of the environment variables
+func interpolate(data []byte) []byte {
+	return interpolation.ReplaceAllFunc(data, func(m []byte) []byte {
+		groups := interpolation.FindSubmatch(m)
+		if value, ok := os.LookupEnv(string(groups[1])); ok {
+			return []byte(value)
+		}
+		return groups[3]
+	})
+}
+
+type field struct {
+	name  string
+	value reflect.Value
+}
+
+// envName converts a field name like "tls.cert" to an environment variable like OAS3_SERVER_TLS_CERT
+func envName(name string) string {
+	return EnvPrefix + strings.ToUpper(strings.Replace(name, ".", "_", -1))
+}
+
+func isSupported(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// fields returns all overridable fields of the struct named by the json tags, joined by "."
+func fields(v reflect.Value, prefix string) []field {
+	var res []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		name = prefix + name
+		fv := v.Field(i)
+		switch {
+		case fv.Kind() == reflect.Struct:
+			res = append(res, fields(fv, name+".")...)
+		case isSupported(fv.Type()):
+			res = append(res, field{name: name, value: fv})
+		}
+	}
+	return res
+}
+
+func setField(v reflect.Value, value string) error {
+	switch {
+	case v.Type() == durationType:
+		// decoded like the config file: a number of seconds or a string like "1m30s"
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			data = []byte(value)
+		}
+		var d Duration
+		if err := d.UnmarshalJSON(data); err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(value)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Int || v.Kind() == reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case v.Kind() == reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	}
+	return nil
+}
+
+// ApplyEnv overrides the config by the environment variables, like OAS3_SERVER_ADDRESS or OAS3_SERVER_TLS_CERT,
+// lists are comma-separated
+func (c *Config) ApplyEnv() error {
+	for _, f := range fields(reflect.ValueOf(c).Elem(), "") {
+		name := envName(f.name)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setField(f.value, value); err != nil {
+			return fmt.Errorf("invalid value of %s: %s", name, err.Error())
+		}
+	}
+	return nil
+}
+
+type flagValue struct {
+	isBool bool
+	value  string
+}
+
+func (f *flagValue) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+func (f *flagValue) Set(value string) error {
+	f.value = value
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	return f.isBool
+}
+
+// RegisterFlags defines a flag for every overridable field of the config, like -address or -tls.cert
+func RegisterFlags(fs *flag.FlagSet) {
+	for _, f := range fields(reflect.ValueOf(&Config{}).Elem(), "") {
+		usage := fmt.Sprintf("overrides '%s' of the config file and %s", f.name, envName(f.name))
+		fs.Var(&flagValue{isBool: f.value.Kind() == reflect.Bool}, f.name, usage)
+	}
+}
+
+// ApplyFlags overrides the config by the flags registered by RegisterFlags and set in the command line
+func (c *Config) ApplyFlags(fs *flag.FlagSet) error {
+	values := make(map[string]reflect.Value)
+	for _, f := range fields(reflect.ValueOf(c).Elem(), "") {
+		values[f.name] = f.value
+	}
+	var err error
+	fs.Visit(func(fl *flag.Flag) {
+		v, ok := values[fl.Name]
+		if !ok || err != nil {
+			return
+		}
+		if sErr := setField(v, fl.Value.String()); sErr != nil {
+			err = fmt.Errorf("invalid value of -%s: %s", fl.Name, sErr.Error())
+		}
+	})
+	return err
+}
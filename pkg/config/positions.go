@@ -0,0 +1,105 @@
+package config
+
+import (
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxPositionsDepth limits the nesting of the walked nodes, the aliases can refer to their parents
+const maxPositionsDepth = 64
+
+// position is a location in the config file, 1-based
+type position struct {
+	line   int
+	column int
+}
+
+// positions maps the key paths like "tls.cert" or "listeners[0].address"
+// of a YAML document to the positions of the keys and of the scalar values,
+// the items of the lists are mapped to the positions of their values
+type positions struct {
+	keys   map[string]position
+	values map[string]position
+}
+
+func (p *positions) find(path string) (position, bool) {
+	if pos, ok := p.values[path]; ok {
+		return pos, true
+	}
+	pos, ok := p.keys[path]
+	return pos, ok
+}
+
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+func nodePosition(node *yaml.Node) position {
+	return position{line: node.Line, column: node.Column}
+}
+
+// walk maps the keys and the items of the node under the path,
+// the aliases are resolved to the positions of their anchors
+func (p *positions) walk(node *yaml.Node, path string, depth int) {
+	if depth > maxPositionsDepth {
+		return
+	}
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Tag == "!!merge" {
+				p.walk(value, path, depth+1)
+				continue
+			}
+			child := joinPath(path, key.Value)
+			p.keys[child] = nodePosition(key)
+			p.value(value, child, depth+1)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			child := path + "[" + strconv.Itoa(i) + "]"
+			p.keys[child] = nodePosition(item)
+			p.value(item, child, depth+1)
+		}
+	}
+}
+
+// value maps the position of the scalar value or walks the collection
+func (p *positions) value(node *yaml.Node, path string, depth int) {
+	target := node
+	if target.Kind == yaml.AliasNode && target.Alias != nil {
+		target = target.Alias
+	}
+	if target.Kind != yaml.ScalarNode {
+		p.walk(node, path, depth)
+		return
+	}
+	if target.Tag != "!!null" || target.Value != "" {
+		p.values[path] = nodePosition(node)
+	}
+}
+
+// newPositions parses the YAML document, no positions are found if it can't be parsed,
+// then the problems are reported without them
+func newPositions(data []byte) *positions {
+	p := positions{
+		keys:   make(map[string]position),
+		values: make(map[string]position),
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return &p
+	}
+	for _, node := range doc.Content {
+		p.walk(node, "", 0)
+	}
+	return &p
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"":                   tls.NoClientCert,
+	"none":               tls.NoClientCert,
+	"request":            tls.RequestClientCert,
+	"require":            tls.RequireAndVerifyClientCert,
+	"verify-if-given":    tls.VerifyClientCertIfGiven,
+	"require-and-verify": tls.RequireAndVerifyClientCert,
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var cipherSuites = map[string]uint16{
+	"TLS_RSA_WITH_AES_128_CBC_SHA":                  tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+	"TLS_RSA_WITH_AES_256_CBC_SHA":                  tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+	"TLS_RSA_WITH_AES_128_GCM_SHA256":               tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	"TLS_RSA_WITH_AES_256_GCM_SHA384":               tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA":          tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA":          tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA":            tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA":            tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256":         tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256":       tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384":         tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384":       tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305":          tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305":        tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256":   tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256": tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+}
+
+// ClientAuthType returns the policy of client certificates,
+// verify-if-given is used if ClientCA is set without ClientAuth
+func (t *TLS) ClientAuthType() (tls.ClientAuthType, error) {
+	if t.ClientAuth == "" && t.ClientCA != "" {
+		return tls.VerifyClientCertIfGiven, nil
+	}
+	auth, ok := clientAuthTypes[t.ClientAuth]
+	if !ok {
+		return tls.NoClientCert, fmt.Errorf("unknown client_auth '%s'", t.ClientAuth)
+	}
+	return auth, nil
+}
+
+// Version returns the minimal TLS version, 0 means the default one
+func (t *TLS) Version() (uint16, error) {
+	if t.MinVersion == "" {
+		return 0, nil
+	}
+	version, ok := tlsVersions[t.MinVersion]
+	if !ok {
+		return 0, fmt.Errorf("unknown min_version '%s'", t.MinVersion)
+	}
+	return version, nil
+}
+
+// CipherSuiteIDs returns the IDs of the cipher suites, nil means the default ones
+func (t *TLS) CipherSuiteIDs() ([]uint16, error) {
+	if len(t.CipherSuites) == 0 {
+		return nil, nil
+	}
+	ids := make([]uint16, len(t.CipherSuites))
+	for i, name := range t.CipherSuites {
+		id, ok := cipherSuites[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite '%s'", name)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// checkClientCA checks that ClientCA is set if and only if the client certificates are verified
+func (t *TLS) checkClientCA(auth tls.ClientAuthType) error {
+	verified := auth == tls.VerifyClientCertIfGiven || auth == tls.RequireAndVerifyClientCert
+	switch {
+	case verified && t.ClientCA == "":
+		return fmt.Errorf("client_ca is required for client_auth '%s'", t.ClientAuth)
+	case !verified && t.ClientCA != "":
+		return fmt.Errorf("client_ca is not used by client_auth '%s'", t.ClientAuth)
+	}
+	return nil
+}
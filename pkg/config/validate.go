@@ -0,0 +1,343 @@
+package config
+
+import (
+	"compress/flate"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// Problem is a single problem of the config file
+type Problem struct {
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (p Problem) String() string {
+	var b strings.Builder
+	if p.Line > 0 {
+		fmt.Fprintf(&b, "%d:%d: ", p.Line, p.Column)
+	}
+	if p.Path != "" {
+		b.WriteString(p.Path)
+		b.WriteString(": ")
+	}
+	b.WriteString(p.Message)
+	return b.String()
+}
+
+// Error contains all problems of the config file
+type Error struct {
+	FileName string
+	// Decoding is true if some values of the file can't be decoded
+	Decoding bool
+	Problems []Problem
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	switch {
+	case e.Decoding:
+		fmt.Fprintf(&b, "unable to unmarshal the file '%s'.", e.FileName)
+	case e.FileName == "":
+		b.WriteString("invalid config.")
+	default:
+		fmt.Fprintf(&b, "invalid config file '%s'.", e.FileName)
+	}
+	fmt.Fprintf(&b, " %d problem(s):", len(e.Problems))
+	for _, p := range e.Problems {
+		b.WriteString("\n\t")
+		if e.FileName != "" && p.Line > 0 {
+			b.WriteString(e.FileName)
+			b.WriteString(":")
+		}
+		b.WriteString(p.String())
+	}
+	return b.String()
+}
+
+type checker struct {
+	positions *positions
+	problems  []Problem
+}
+
+func (c *checker) sort() {
+	sort.SliceStable(c.problems, func(i, j int) bool {
+		a, b := c.problems[i], c.problems[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Path < b.Path
+	})
+}
+
+func (c *checker) addAt(path string, pos position, format string, args ...interface{}) {
+	c.problems = append(c.problems, Problem{
+		Path:    path,
+		Line:    pos.line,
+		Column:  pos.column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// add reports a problem of a value
+func (c *checker) add(path, format string, args ...interface{}) {
+	var pos position
+	if c.positions != nil {
+		pos, _ = c.positions.find(path)
+	}
+	c.addAt(path, pos, format, args...)
+}
+
+// addKey reports a problem of a key
+func (c *checker) addKey(path, format string, args ...interface{}) {
+	var pos position
+	if c.positions != nil {
+		pos = c.positions.keys[path]
+	}
+	c.addAt(path, pos, format, args...)
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "list"
+	default:
+		return "object"
+	}
+}
+
+func expectedType(t reflect.Type) string {
+	if t == durationType {
+		return "duration"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int64, reflect.Uint16:
+		return "integer"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		return "list"
+	default:
+		return "object"
+	}
+}
+
+func fieldByName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag == name && tag != "-" {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// check validates the decoded value v against the type t,
+// the invalid values are removed to decode the rest of the file
+func (c *checker) check(v interface{}, t reflect.Type, path string) (interface{}, bool) {
+	switch {
+	case t.Kind() == reflect.Struct:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			c.add(path, "expected an object, got %s", jsonType(v))
+			return nil, false
+		}
+		for key, value := range obj {
+			f, ok := fieldByName(t, key)
+			if !ok {
+				c.addKey(joinPath(path, key), "unknown field")
+				delete(obj, key)
+				continue
+			}
+			if value, ok = c.check(value, f.Type, joinPath(path, key)); ok {
+				obj[key] = value
+			} else {
+				delete(obj, key)
+			}
+		}
+		return obj, true
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct:
+		list, ok := v.([]interface{})
+		if !ok {
+			c.add(path, "expected a list, got %s", jsonType(v))
+			return nil, false
+		}
+		res := make([]interface{}, 0, len(list))
+		for i, item := range list {
+			if item, ok = c.check(item, t.Elem(), path+"["+strconv.Itoa(i)+"]"); ok {
+				res = append(res, item)
+			} else {
+				res = append(res, map[string]interface{}{})
+			}
+		}
+		return res, true
+	}
+	data, err := json.Marshal(v)
+	if err == nil {
+		err = json.Unmarshal(data, reflect.New(t).Interface())
+	}
+	if err != nil {
+		if t == durationType && jsonType(v) == "string" {
+			c.add(path, "invalid duration %s", string(data))
+		} else {
+			c.add(path, "expected %s, got %s %s", expectedType(t), jsonType(v), string(data))
+		}
+		return nil, false
+	}
+	return v, true
+}
+
+// decodeStrict decodes the YAML into the config,
+// reports all unknown fields and invalid values with their positions
+func decodeStrict(data []byte, cfg *Config) (*positions, []Problem, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	var raw interface{}
+	if err := json.Unmarshal(jsonData, &raw); err != nil {
+		return nil, nil, err
+	}
+	if raw == nil {
+		return nil, nil, nil
+	}
+	c := checker{positions: newPositions(data)}
+	cleaned, ok := c.check(raw, reflect.TypeOf(*cfg), "")
+	if !ok {
+		return c.positions, c.problems, nil
+	}
+	if jsonData, err = json.Marshal(cleaned); err != nil {
+		return nil, nil, err
+	}
+	if err := json.Unmarshal(jsonData, cfg); err != nil {
+		return nil, nil, err
+	}
+	return c.positions, c.problems, nil
+}
+
+func (c *checker) checkFile(path, fileName string, dir bool) {
+	info, err := os.Stat(fileName)
+	switch {
+	case err != nil:
+		c.add(path, "%s", err.Error())
+	case dir && !info.IsDir():
+		c.add(path, "'%s' is not a directory", fileName)
+	case !dir && info.IsDir():
+		c.add(path, "'%s' is a directory", fileName)
+	}
+}
+
+func (c *checker) checkTLS(tls *TLS, path string) {
+	if tls.Enabled {
+		if tls.Cert == "" {
+			c.add(joinPath(path, "enabled"), "cert is required if TLS is enabled")
+		} else {
+			c.checkFile(joinPath(path, "cert"), tls.Cert, false)
+		}
+		if tls.Key == "" {
+			c.add(joinPath(path, "enabled"), "key is required if TLS is enabled")
+		} else {
+			c.checkFile(joinPath(path, "key"), tls.Key, false)
+		}
+		if tls.ClientCA != "" {
+			c.checkFile(joinPath(path, "client_ca"), tls.ClientCA, false)
+		}
+	}
+	if auth, err := tls.ClientAuthType(); err != nil {
+		c.add(joinPath(path, "client_auth"), "%s", err.Error())
+	} else if err := tls.checkClientCA(auth); err != nil {
+		c.add(joinPath(path, "client_auth"), "%s", err.Error())
+	}
+	if _, err := tls.Version(); err != nil {
+		c.add(joinPath(path, "min_version"), "%s", err.Error())
+	}
+	if _, err := tls.CipherSuiteIDs(); err != nil {
+		c.add(joinPath(path, "cipher_suites"), "%s", err.Error())
+	}
+}
+
+// validate checks the values and the rules between the fields
+func (c *checker) validate(cfg *Config) {
+	c.checkTLS(&cfg.TLS, "tls")
+	if cfg.Timeout < 0 {
+		c.add("timeout", "must not be negative")
+	}
+	if cfg.MaxBodySize < 0 {
+		c.add("max_body_size", "must not be negative")
+	}
+	if cfg.Compress.Level < flate.HuffmanOnly || cfg.Compress.Level > flate.BestCompression {
+		c.add("compress.level", "must be from -2 to 9")
+	}
+	if cfg.Static != "" {
+		c.checkFile("static", cfg.Static, true)
+	}
+	hasTLS := false
+	for _, l := range cfg.Listeners {
+		hasTLS = hasTLS || l.TLS.Enabled && l.Socket == ""
+	}
+	for i := range cfg.Listeners {
+		l := &cfg.Listeners[i]
+		path := "listeners[" + strconv.Itoa(i) + "]"
+		switch {
+		case l.Address == "" && l.Socket == "":
+			c.add(path, "address or socket is required")
+		case l.Address != "" && l.Socket != "":
+			c.add(path, "address and socket are mutually exclusive")
+		}
+		switch {
+		case l.RedirectHTTPS && l.TLS.Enabled:
+			c.add(joinPath(path, "redirect_https"), "a TLS listener can't redirect to HTTPS")
+		case l.RedirectHTTPS && !hasTLS:
+			c.add(joinPath(path, "redirect_https"), "a TLS listener with an address is required to redirect to HTTPS")
+		}
+		c.checkTLS(&l.TLS, joinPath(path, "tls"))
+	}
+	names := make(map[string]bool)
+	for i := range cfg.APIs {
+		api := &cfg.APIs[i]
+		path := "apis[" + strconv.Itoa(i) + "]"
+		switch {
+		case api.Name == "":
+			c.add(path, "name is required")
+		case names[api.Name]:
+			c.add(joinPath(path, "name"), "duplicated name '%s'", api.Name)
+		}
+		names[api.Name] = true
+		if api.OAS3 == "" && len(api.Specs) == 0 && api.Model == nil {
+			c.add(path, "oas3 or specs is required")
+		}
+	}
+}
+
+// Check validates the values and the rules between the fields of the config like LoadWithFlags,
+// it is used for the configs built in code, the problems have no positions
+func (c *Config) Check() error {
+	var ch checker
+	ch.validate(c)
+	if len(ch.problems) > 0 {
+		return &Error{Problems: ch.problems}
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package gen
+
+const clientTemplate = `{{range .Operations}}
+// {{.Name}}Result is the result of the operation '{{.ID}}'
+type {{.Name}}Result struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the raw body of the response
+	Body []byte
+{{- range .Responses}}
+	// {{.Field}} is the body of the {{if .Default}}default{{else}}{{.Code}}{{end}} response
+	{{.Field}} {{if .Pointer}}*{{end}}{{.Type}}
+{{- end}}
+}
+{{end}}
+// Client calls the operations of the API
+type Client struct {
+	// BaseURL is the URL of the server including the base path
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a Client, http.DefaultClient is used if httpClient is nil
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: httpClient,
+	}
+}
+{{range .Operations}}
+// {{.Name}} calls the operation '{{.ID}}'{{if .Summary}}: {{.Summary}}{{end}}
+func (c *Client) {{.Name}}(ctx context.Context, req *{{.Name}}Request) (*{{.Name}}Result, error) {
+	if req == nil {
+		req = &{{.Name}}Request{}
+	}
+	path := "{{.Path}}"
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []*http.Cookie
+{{- range .Params}}
+{{- if .Optional}}
+	if req.{{.Field}} != nil {
+{{- end}}
+{{- if eq .In "path"}}
+	path = strings.Replace(path, "{{"{"}}{{.Name}}{{"}"}}", formatPath("{{.Style}}", {{.Explode}}, "{{.Name}}", req.{{.Field}}), 1)
+{{- else if eq .In "query"}}
+	addQuery(query, "{{.Style}}", {{.Explode}}, "{{.Name}}", req.{{.Field}})
+{{- else if eq .In "header"}}
+	header.Set("{{.Name}}", strings.Join(formatValues(req.{{.Field}}), ","))
+{{- else if eq .In "cookie"}}
+	cookies = append(cookies, &http.Cookie{Name: "{{.Name}}", Value: strings.Join(formatValues(req.{{.Field}}), ",")})
+{{- end}}
+{{- if .Optional}}
+	}
+{{- end}}
+{{- end}}
+	var body []byte
+{{- if .Body}}
+	if req.Body != nil {
+		data, err := encodeBody("{{.BodyContentType}}", req.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+		header.Set("Content-Type", "{{.BodyContentType}}")
+	}
+{{- end}}
+	resp, data, err := c.do(ctx, "{{.Method}}", path, query, header, cookies, body)
+	if err != nil {
+		return nil, err
+	}
+	result := {{.Name}}Result{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       data,
+	}
+{{- if .Responses}}
+	switch resp.StatusCode {
+{{- range .Responses}}
+{{- if .Default}}
+	default:
+{{- else}}
+	case {{.Code}}:
+{{- end}}
+		err = decodeResult(data, &result.{{.Field}})
+{{- end}}
+	}
+{{- end}}
+	return &result, err
+}
+{{end}}
+func (c *Client) do(
+	ctx context.Context,
+	method, path string,
+	query url.Values,
+	header http.Header,
+	cookies []*http.Cookie,
+	body []byte,
+) (*http.Response, []byte, error) {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, data, nil
+}
+
+// formatValues converts a scalar or an array to the strings
+func formatValues(value interface{}) []string {
+	rv := reflect.Indirect(reflect.ValueOf(value))
+	if rv.Kind() != reflect.Slice {
+		return []string{formatValue(rv)}
+	}
+	values := make([]string, rv.Len())
+	for i := range values {
+		values[i] = formatValue(reflect.Indirect(rv.Index(i)))
+	}
+	return values
+}
+
+func formatValue(rv reflect.Value) string {
+	switch rv.Kind() {
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+// formatPath serializes the path parameter with the simple, label or matrix style
+func formatPath(style string, explode bool, name string, value interface{}) string {
+	values := formatValues(value)
+	for i, v := range values {
+		values[i] = url.PathEscape(v)
+	}
+	switch style {
+	case "label":
+		if explode {
+			return "." + strings.Join(values, ".")
+		}
+		return "." + strings.Join(values, ",")
+	case "matrix":
+		if explode {
+			return ";" + name + "=" + strings.Join(values, ";"+name+"=")
+		}
+		return ";" + name + "=" + strings.Join(values, ",")
+	}
+	return strings.Join(values, ",")
+}
+
+// addQuery serializes the query parameter with the form, spaceDelimited or pipeDelimited style
+func addQuery(query url.Values, style string, explode bool, name string, value interface{}) {
+	values := formatValues(value)
+	switch {
+	case explode:
+		query[name] = values
+	case style == "spaceDelimited":
+		query.Set(name, strings.Join(values, " "))
+	case style == "pipeDelimited":
+		query.Set(name, strings.Join(values, "|"))
+	default:
+		query.Set(name, strings.Join(values, ","))
+	}
+}
+
+// encodeBody encodes the body by the media type
+func encodeBody(contentType string, body interface{}) ([]byte, error) {
+	if contentType != "application/x-www-form-urlencoded" {
+		return json.Marshal(body)
+	}
+	form, err := encodeForm(body)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(form.Encode()), nil
+}
+
+// encodeForm converts the fields of the struct to the form values named as the JSON fields
+func encodeForm(body interface{}) (url.Values, error) {
+	rv := reflect.Indirect(reflect.ValueOf(body))
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.New("the form body must be an object")
+	}
+	form := url.Values{}
+	for i := 0; i < rv.NumField(); i++ {
+		name := strings.Split(rv.Type().Field(i).Tag.Get("json"), ",")[0]
+		field := rv.Field(i)
+		if (field.Kind() == reflect.Ptr || field.Kind() == reflect.Slice) && field.IsNil() {
+			continue
+		}
+		form[name] = formatValues(field.Interface())
+	}
+	return form, nil
+}
+
+func decodeResult(data []byte, target interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, target)
+}
+`
@@ -0,0 +1,563 @@
+package gen
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var initialisms = map[string]bool{
+	"api":  true,
+	"html": true,
+	"http": true,
+	"id":   true,
+	"json": true,
+	"uri":  true,
+	"url":  true,
+	"uuid": true,
+}
+
+var methods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+}
+
+// GoName converts a name from the specification to an exported Go identifier,
+// e.g. 'wiki.view' becomes 'WikiView' and 'X-Request-ID' becomes 'XRequestID'
+func GoName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var builder strings.Builder
+	for _, part := range parts {
+		if initialisms[strings.ToLower(part)] {
+			builder.WriteString(strings.ToUpper(part))
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		builder.WriteString(string(runes))
+	}
+	res := builder.String()
+	if res == "" {
+		return "X"
+	}
+	if unicode.IsDigit([]rune(res)[0]) {
+		return "N" + res
+	}
+	return res
+}
+
+// Param is a parameter of an operation
+type Param struct {
+	Name     string
+	In       string
+	Field    string
+	Type     string
+	Required bool
+	Style    string
+	Explode  bool
+}
+
+// Pointer checks if the optional parameter is stored by a pointer
+func (p Param) Pointer() bool {
+	return !p.Required && !strings.HasPrefix(p.Type, "[]")
+}
+
+// Optional checks if the parameter can be omitted
+func (p Param) Optional() bool {
+	return !p.Required
+}
+
+// Prefix is the beginning of the serialized value added by the label and matrix styles
+func (p Param) Prefix() string {
+	switch p.Style {
+	case "label":
+		return "."
+	case "matrix":
+		return ";" + p.Name + "="
+	}
+	return ""
+}
+
+// Separator is the delimiter of the items of the serialized array after the prefix,
+// the items of the exploded query parameters are the repeated values, so they have no separator
+func (p Param) Separator() string {
+	if p.Explode {
+		switch {
+		case p.Style == "label":
+			return "."
+		case p.Style == "matrix":
+			return ";" + p.Name + "="
+		case p.In == openapi3.ParameterInQuery:
+			return ""
+		}
+		return ","
+	}
+	switch p.Style {
+	case "spaceDelimited":
+		return " "
+	case "pipeDelimited":
+		return "|"
+	}
+	return ","
+}
+
+// paramStyles are the supported styles of the parameters by their locations
+var paramStyles = map[string]map[string]bool{
+	openapi3.ParameterInPath:   {"simple": true, "label": true, "matrix": true},
+	openapi3.ParameterInQuery:  {"form": true, "spaceDelimited": true, "pipeDelimited": true},
+	openapi3.ParameterInHeader: {"simple": true},
+	openapi3.ParameterInCookie: {"form": true},
+}
+
+// Response is a typed body of the response with the status code
+type Response struct {
+	Code    string
+	Field   string
+	Type    string
+	Pointer bool
+}
+
+// Default checks if the response is used for all undeclared status codes
+func (r Response) Default() bool {
+	return r.Code == "default"
+}
+
+// Operation is an operation of the specification with the Go types of its request and response
+type Operation struct {
+	ID      string
+	Name    string
+	Method  string
+	Path    string
+	Summary string
+	Params  []Param
+
+	// Body is the type of the JSON or form request body, empty if the operation doesn't accept them
+	Body            string
+	BodyPointer     bool
+	BodyRequired    bool
+	BodyContentType string
+
+	// Status is the status code of the successful response
+	Status int
+	// Result is the type of the JSON body of the successful response
+	Result        string
+	ResultPointer bool
+	// ResultContentType is the content type of the successful response if it isn't JSON
+	ResultContentType string
+	// Responses are the JSON bodies of all responses
+	Responses []Response
+}
+
+// Type is a named type generated from a schema
+type Type struct {
+	Name        string
+	Description string
+	Definition  string
+}
+
+type generator struct {
+	model      *openapi3.Swagger
+	types      map[string]*Type
+	schemas    map[string]*openapi3.Schema
+	refs       map[string]string
+	pending    []string
+	err        error
+	operations []*Operation
+}
+
+func schemaName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func isJSON(contentType string) bool {
+	return contentType == "application/json" || strings.HasSuffix(contentType, "+json")
+}
+
+func isForm(contentType string) bool {
+	return contentType == "application/x-www-form-urlencoded"
+}
+
+func sortedContentTypes(content openapi3.Content) []string {
+	contentTypes := make([]string, 0, len(content))
+	for contentType, mediaType := range content {
+		if mediaType != nil {
+			contentTypes = append(contentTypes, contentType)
+		}
+	}
+	sort.Strings(contentTypes)
+	return contentTypes
+}
+
+// jsonContentType finds the JSON media type of the content
+func jsonContentType(content openapi3.Content) string {
+	for _, contentType := range sortedContentTypes(content) {
+		if isJSON(contentType) {
+			return contentType
+		}
+	}
+	return ""
+}
+
+// nameOf registers the named type of the referenced schema,
+// the different schemas with the same Go name are recorded as an error of the generator
+func (g *generator) nameOf(ref string, schema *openapi3.Schema) string {
+	name := GoName(schemaName(ref))
+	registered, ok := g.schemas[name]
+	if !ok {
+		g.schemas[name] = schema
+		g.refs[name] = ref
+		g.pending = append(g.pending, name)
+	} else if registered != schema && g.err == nil {
+		g.err = fmt.Errorf("schemas '%s' and '%s' have the same Go name '%s'", g.refs[name], ref, name)
+	}
+	return name
+}
+
+// typeOf returns the Go type of the schema, the referenced schemas become the named types
+func (g *generator) typeOf(ref *openapi3.SchemaRef) string {
+	if ref == nil || ref.Value == nil {
+		return "interface{}"
+	}
+	if ref.Ref != "" {
+		return g.nameOf(ref.Ref, ref.Value)
+	}
+	return g.definition(ref.Value)
+}
+
+// pointer checks if an optional value of the schema needs a pointer to be omitted
+func pointer(ref *openapi3.SchemaRef) bool {
+	if ref == nil || ref.Value == nil {
+		return false
+	}
+	switch ref.Value.Type {
+	case "string", "integer", "number", "boolean":
+		return true
+	case "object", "":
+		return len(ref.Value.Properties) > 0
+	}
+	return false
+}
+
+func (g *generator) definition(schema *openapi3.Schema) string {
+	switch schema.Type {
+	case "string":
+		return "string"
+	case "integer":
+		if schema.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if schema.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.typeOf(schema.Items)
+	}
+	if len(schema.Properties) > 0 {
+		return g.structOf(schema)
+	}
+	if schema.AdditionalProperties != nil {
+		return "map[string]" + g.typeOf(schema.AdditionalProperties)
+	}
+	if schema.Type == "object" {
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+func (g *generator) structOf(schema *openapi3.Schema) string {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	required := make(map[string]bool)
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	var builder strings.Builder
+	builder.WriteString("struct {\n")
+	for _, name := range names {
+		prop := schema.Properties[name]
+		typ := g.typeOf(prop)
+		tag := name
+		if !required[name] {
+			tag += ",omitempty"
+			if pointer(prop) {
+				typ = "*" + typ
+			}
+		} else if prop.Value != nil && prop.Value.Nullable && pointer(prop) {
+			typ = "*" + typ
+		}
+		fmt.Fprintf(&builder, "%s %s `json:%q`\n", GoName(name), typ, tag)
+	}
+	builder.WriteString("}")
+	return builder.String()
+}
+
+func (g *generator) param(ref *openapi3.ParameterRef) (Param, error) {
+	if ref == nil || ref.Value == nil {
+		return Param{}, fmt.Errorf("invalid parameter")
+	}
+	p := ref.Value
+	typ := "string"
+	if p.Schema != nil {
+		typ = g.typeOf(p.Schema)
+	}
+	style := p.Style
+	if style == "" {
+		switch p.In {
+		case openapi3.ParameterInQuery, openapi3.ParameterInCookie:
+			style = "form"
+		default:
+			style = "simple"
+		}
+	}
+	if !paramStyles[p.In][style] {
+		return Param{}, fmt.Errorf("the style '%s' of the %s parameter '%s' is not supported", style, p.In, p.Name)
+	}
+	explode := style == "form"
+	if p.Explode != nil {
+		explode = *p.Explode
+	}
+	return Param{
+		Name:     p.Name,
+		In:       p.In,
+		Field:    GoName(p.Name),
+		Type:     typ,
+		Required: p.Required || p.In == openapi3.ParameterInPath,
+		Style:    style,
+		Explode:  explode,
+	}, nil
+}
+
+// success finds the successful response with the lowest status code
+func success(responses openapi3.Responses) (int, *openapi3.Response) {
+	codes := make([]int, 0, len(responses))
+	for code := range responses {
+		status, err := strconv.Atoi(code)
+		if err == nil && status >= 200 && status < 300 {
+			codes = append(codes, status)
+		}
+	}
+	if len(codes) == 0 {
+		return http.StatusOK, nil
+	}
+	sort.Ints(codes)
+	ref := responses[strconv.Itoa(codes[0])]
+	if ref == nil {
+		return codes[0], nil
+	}
+	return codes[0], ref.Value
+}
+
+func (g *generator) operation(path, method string, pathItem *openapi3.PathItem, op *openapi3.Operation) error {
+	operation := Operation{
+		ID:      op.OperationID,
+		Name:    GoName(op.OperationID),
+		Method:  method,
+		Path:    path,
+		Summary: op.Summary,
+	}
+	seen := make(map[string]bool)
+	for _, parameters := range []openapi3.Parameters{op.Parameters, pathItem.Parameters} {
+		for _, ref := range parameters {
+			p, err := g.param(ref)
+			if err != nil {
+				return fmt.Errorf("operation '%s': %v", op.OperationID, err)
+			}
+			if seen[p.In+"/"+p.Name] {
+				continue
+			}
+			seen[p.In+"/"+p.Name] = true
+			operation.Params = append(operation.Params, p)
+		}
+	}
+	sort.SliceStable(operation.Params, func(i, j int) bool {
+		return operation.Params[i].Field < operation.Params[j].Field
+	})
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		content := op.RequestBody.Value.Content
+		contentType := jsonContentType(content)
+		if contentType == "" && content.Get("application/x-www-form-urlencoded") != nil {
+			contentType = "application/x-www-form-urlencoded"
+		}
+		if contentType != "" {
+			operation.Body = g.typeOf(content[contentType].Schema)
+			operation.BodyPointer = pointer(content[contentType].Schema)
+			operation.BodyRequired = op.RequestBody.Value.Required
+			operation.BodyContentType = contentType
+		}
+	}
+	status, response := success(op.Responses)
+	operation.Status = status
+	if response != nil && len(response.Content) > 0 {
+		if contentType := jsonContentType(response.Content); contentType != "" {
+			operation.Result = g.typeOf(response.Content[contentType].Schema)
+			operation.ResultPointer = pointer(response.Content[contentType].Schema)
+		} else {
+			operation.ResultContentType = sortedContentTypes(response.Content)[0]
+		}
+	}
+	operation.Responses = g.responses(op.Responses)
+	g.operations = append(g.operations, &operation)
+	return nil
+}
+
+// responses returns the JSON bodies of the responses ordered by the status codes, the default response is the last
+func (g *generator) responses(responses openapi3.Responses) []Response {
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if codes[i] == "default" || codes[j] == "default" {
+			return codes[j] == "default" && codes[i] != "default"
+		}
+		return codes[i] < codes[j]
+	})
+	var res []Response
+	for _, code := range codes {
+		ref := responses[code]
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		contentType := jsonContentType(ref.Value.Content)
+		if contentType == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(code); err != nil && code != "default" {
+			continue
+		}
+		schema := ref.Value.Content[contentType].Schema
+		field := "JSON" + code
+		if code == "default" {
+			field = "JSONDefault"
+		}
+		res = append(res, Response{
+			Code:    code,
+			Field:   field,
+			Type:    g.typeOf(schema),
+			Pointer: pointer(schema),
+		})
+	}
+	return res
+}
+
+// reserved returns the names declared by the generated code
+func reserved(operations []*Operation) map[string]string {
+	names := map[string]string{
+		"Interface":   "the interface",
+		"Register":    "the adapter",
+		"StatusError": "the error type",
+		"Client":      "the client",
+		"NewClient":   "the client",
+	}
+	for _, op := range operations {
+		for _, suffix := range []string{"Request", "Response", "Result"} {
+			names[op.Name+suffix] = "the operation '" + op.ID + "'"
+		}
+	}
+	return names
+}
+
+// newGenerator collects the operations and the types of the model
+func newGenerator(model *openapi3.Swagger) (*generator, error) {
+	g := generator{
+		model:   model,
+		types:   make(map[string]*Type),
+		schemas: make(map[string]*openapi3.Schema),
+		refs:    make(map[string]string),
+	}
+	paths := make([]string, 0, len(model.Paths))
+	for path := range model.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	ids := make(map[string]string)
+	for _, path := range paths {
+		pathItem := model.Paths[path]
+		if pathItem == nil {
+			continue
+		}
+		for _, method := range methods {
+			op := pathItem.GetOperation(method)
+			if op == nil || op.OperationID == "" || strings.HasPrefix(op.OperationID, "oas3.") {
+				continue
+			}
+			name := GoName(op.OperationID)
+			if id, ok := ids[name]; ok {
+				if id != op.OperationID {
+					return nil, fmt.Errorf("operations '%s' and '%s' have the same Go name '%s'", id, op.OperationID, name)
+				}
+				continue
+			}
+			ids[name] = op.OperationID
+			if err := g.operation(path, method, pathItem, op); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if model.Components.Schemas != nil {
+		names := make([]string, 0, len(model.Components.Schemas))
+		for name := range model.Components.Schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if ref := model.Components.Schemas[name]; ref != nil && ref.Value != nil {
+				g.nameOf("#/components/schemas/"+name, ref.Value)
+			}
+		}
+	}
+	for len(g.pending) > 0 {
+		name := g.pending[0]
+		g.pending = g.pending[1:]
+		schema := g.schemas[name]
+		g.types[name] = &Type{
+			Name:        name,
+			Description: strings.TrimSpace(strings.SplitN(schema.Description, "\n", 2)[0]),
+			Definition:  g.definition(schema),
+		}
+	}
+	if g.err != nil {
+		return nil, g.err
+	}
+	names := reserved(g.operations)
+	for name := range g.types {
+		if origin, ok := names[name]; ok {
+			return nil, fmt.Errorf("the schema '%s' conflicts with the type of %s", name, origin)
+		}
+	}
+	return &g, nil
+}
+
+// sortedTypes returns the named types ordered by their names
+func (g *generator) sortedTypes() []*Type {
+	types := make([]*Type, 0, len(g.types))
+	for _, t := range g.types {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Name < types[j].Name
+	})
+	return types
+}
@@ -0,0 +1,75 @@
+package gen
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SVilgelm/oas3-server/pkg/oas3"
+)
+
+func TestGoName(t *testing.T) {
+	t.Parallel()
+	for name, expected := range map[string]string{
+		"wiki.view":    "WikiView",
+		"X-Request-ID": "XRequestID",
+		"listTodos":    "ListTodos",
+		"user_url":     "UserURL",
+		"2fa":          "N2fa",
+		"-":            "X",
+	} {
+		assert.Equal(t, expected, GoName(name), name)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	model, err := oas3.Load("../../examples/todo/todo.yaml")
+	assert.NoError(t, err)
+	for file, opts := range map[string]Options{
+		"server.gen.go":           {Package: "api", Server: true},
+		"../client/client.gen.go": {Package: "client", Client: true},
+	} {
+		src, err := Generate(model, opts)
+		assert.NoError(t, err)
+		expected, err := ioutil.ReadFile("../../examples/todo/api/" + file)
+		assert.NoError(t, err)
+		assert.Equal(t, string(expected), string(src), "run go generate ./examples/...")
+	}
+
+	src, err := Generate(model, Options{Package: "api", Server: true, Client: true})
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "func Register(")
+	assert.Contains(t, string(src), "func NewClient(")
+
+	model, err = oas3.Load("internal/styles/styles.yaml")
+	assert.NoError(t, err)
+	src, err = Generate(model, Options{Package: "api", Server: true})
+	assert.NoError(t, err)
+	expected, err := ioutil.ReadFile("internal/styles/api/server.gen.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(src), "run go generate ./pkg/gen/...")
+}
+
+func TestServerErrors(t *testing.T) {
+	t.Parallel()
+	model, err := oas3.Load("testdata/conflict.yaml")
+	assert.NoError(t, err)
+	_, err = Generate(model, Options{Package: "api", Server: true})
+	assert.EqualError(t, err, "the schema 'ItemsListResponse' conflicts with the type of the operation 'items.list'")
+
+	_, err = Generate(&openapi3.Swagger{}, Options{Package: "api", Server: true})
+	assert.EqualError(t, err, "no operations to generate")
+
+	model, err = oas3.Load("testdata/collision.yaml")
+	assert.NoError(t, err)
+	_, err = Generate(model, Options{Package: "api", Server: true})
+	assert.EqualError(t, err, "schemas '#/components/schemas/item' and '#/components/schemas/Item' have the same Go name 'Item'")
+
+	model, err = oas3.Load("testdata/deepobject.yaml")
+	assert.NoError(t, err)
+	_, err = Generate(model, Options{Package: "api", Server: true})
+	assert.EqualError(t, err, "operation 'items.list': the style 'deepObject' of the query parameter 'filter' is not supported")
+}
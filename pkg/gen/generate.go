@@ -0,0 +1,174 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const headerTemplate = `// Code generated by oas3-gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+{{if .ExternalImports}}
+{{- range .ExternalImports}}
+	"{{.}}"
+{{- end}}
+{{end}}
+{{- if .LocalImports}}
+{{- range .LocalImports}}
+	"{{.}}"
+{{- end}}
+{{end -}}
+)
+`
+
+const typesTemplate = `{{range .Types}}
+{{if .Description}}// {{.Name}} {{.Description}}{{else}}// {{.Name}} is generated from the schema '{{.Name}}'{{end}}
+type {{.Name}} {{.Definition}}
+{{end}}
+{{range .Operations}}
+// {{.Name}}Request is the request of the operation '{{.ID}}'
+type {{.Name}}Request struct {
+{{- range .Params}}
+	{{.Field}} {{if .Pointer}}*{{end}}{{.Type}}
+{{- end}}
+{{- if .Body}}
+	Body {{if .BodyPointer}}*{{end}}{{.Body}}
+{{- end}}
+	// HTTPRequest is the original request, set by the server
+	HTTPRequest *http.Request
+}
+{{end}}`
+
+var templates = template.Must(template.New("header").Parse(headerTemplate))
+
+func init() {
+	template.Must(templates.New("types").Parse(typesTemplate))
+	template.Must(templates.New("server").Parse(serverTemplate))
+	template.Must(templates.New("client").Parse(clientTemplate))
+}
+
+var (
+	serverImports = []string{
+		"context",
+		"encoding/json",
+		"errors",
+		"fmt",
+		"github.com/gorilla/mux",
+		"github.com/SVilgelm/oas3-server/pkg/oas3",
+		"github.com/SVilgelm/oas3-server/pkg/server",
+		"io",
+		"net/http",
+		"net/url",
+		"reflect",
+		"strconv",
+		"strings",
+	}
+	clientImports = []string{
+		"bytes",
+		"context",
+		"encoding/json",
+		"errors",
+		"fmt",
+		"io/ioutil",
+		"net/http",
+		"net/url",
+		"reflect",
+		"strconv",
+		"strings",
+	}
+)
+
+// Options selects the generated code, the types of the schemas and the requests are always generated
+type Options struct {
+	// Package is the name of the package of the generated code
+	Package string
+	// Server generates the interface of the handlers and the Register adapter
+	Server bool
+	// Client generates the Client with one method per operation
+	Client bool
+}
+
+type templateData struct {
+	Package         string
+	Imports         []string
+	ExternalImports []string
+	LocalImports    []string
+	Types           []*Type
+	Operations      []*Operation
+}
+
+// imports groups the imports of the generated code as goimports does
+func (d *templateData) imports(opts Options) {
+	set := map[string]bool{"net/http": true}
+	if opts.Server {
+		for _, imp := range serverImports {
+			set[imp] = true
+		}
+	}
+	if opts.Client {
+		for _, imp := range clientImports {
+			set[imp] = true
+		}
+	}
+	for imp := range set {
+		switch {
+		case strings.HasPrefix(imp, "github.com/SVilgelm/oas3-server/"):
+			d.LocalImports = append(d.LocalImports, imp)
+		case strings.Contains(imp, "."):
+			d.ExternalImports = append(d.ExternalImports, imp)
+		default:
+			d.Imports = append(d.Imports, imp)
+		}
+	}
+	sort.Strings(d.Imports)
+	sort.Strings(d.ExternalImports)
+	sort.Strings(d.LocalImports)
+}
+
+// Generate generates the Go code of the model: the types of the schemas and the requests,
+// the typed server interface with the Register adapter and the client
+func Generate(model *openapi3.Swagger, opts Options) ([]byte, error) {
+	g, err := newGenerator(model)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.operations) == 0 {
+		return nil, errors.New("no operations to generate")
+	}
+	data := templateData{
+		Package:    opts.Package,
+		Types:      g.sortedTypes(),
+		Operations: g.operations,
+	}
+	data.imports(opts)
+	names := []string{"header", "types"}
+	if opts.Server {
+		names = append(names, "server")
+	}
+	if opts.Client {
+		names = append(names, "client")
+	}
+	var buf bytes.Buffer
+	for _, name := range names {
+		if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+			return nil, err
+		}
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid generated code: %v", err)
+	}
+	return src, nil
+}
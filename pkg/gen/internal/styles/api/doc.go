@@ -0,0 +1,4 @@
+// Package api is generated from the specification using all supported parameter styles
+package api
+
+//go:generate go run ../../../../../cmd/oas3-gen -spec ../styles.yaml -package api -out server.gen.go
@@ -0,0 +1,368 @@
+// Code generated by oas3-gen. DO NOT EDIT.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/SVilgelm/oas3-server/pkg/oas3"
+	"github.com/SVilgelm/oas3-server/pkg/server"
+)
+
+// Echo is generated from the schema 'Echo'
+type Echo struct {
+	Flags          Words   `json:"flags,omitempty"`
+	Form           Words   `json:"form,omitempty"`
+	FormFlat       Words   `json:"formFlat,omitempty"`
+	Kind           string  `json:"kind"`
+	Label          Numbers `json:"label"`
+	LabelExploded  Numbers `json:"labelExploded"`
+	Matrix         Numbers `json:"matrix"`
+	MatrixExploded Numbers `json:"matrixExploded"`
+	Pipe           Words   `json:"pipe,omitempty"`
+	Session        *string `json:"session,omitempty"`
+	Simple         Numbers `json:"simple"`
+	Space          Words   `json:"space,omitempty"`
+	SpaceExploded  Words   `json:"spaceExploded,omitempty"`
+}
+
+// Numbers is generated from the schema 'Numbers'
+type Numbers []int64
+
+// Words is generated from the schema 'Words'
+type Words []string
+
+// StylesEchoRequest is the request of the operation 'styles.echo'
+type StylesEchoRequest struct {
+	Form           []string
+	FormFlat       []string
+	Kind           string
+	Label          []int64
+	LabelExploded  []int64
+	Matrix         []int64
+	MatrixExploded []int64
+	Pipe           []string
+	Session        *string
+	Simple         []int64
+	Space          []string
+	SpaceExploded  []string
+	XFlags         []string
+	// HTTPRequest is the original request, set by the server
+	HTTPRequest *http.Request
+}
+
+// StylesEchoResponse is the response of the operation 'styles.echo'
+type StylesEchoResponse struct {
+	// Status is 200 if not set
+	Status int
+	Header http.Header
+	Body   *Echo
+}
+
+// Interface is implemented by the handlers of the operations
+type Interface interface {
+	// StylesEcho handles the operation 'styles.echo': Echoes the parameters serialized by all supported styles
+	StylesEcho(ctx context.Context, req *StylesEchoRequest) (*StylesEchoResponse, error)
+}
+
+// StatusError is returned by the handlers to respond with the status code
+type StatusError struct {
+	Status  int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
+// Register links the handlers of the implementation with the operations of the server
+func Register(srv *server.Server, impl Interface) error {
+	if err := srv.Handle("styles.echo", handleStylesEcho(impl)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func handleStylesEcho(impl Interface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := StylesEchoRequest{HTTPRequest: r}
+		if values, ok := queryValues(r, "form"); ok {
+			if err := parseValues(values, "", "", &req.Form); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid query parameter 'form': " + err.Error()})
+				return
+			}
+		}
+		if values, ok := queryValues(r, "formFlat"); ok {
+			if err := parseValues(values, "", ",", &req.FormFlat); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid query parameter 'formFlat': " + err.Error()})
+				return
+			}
+		}
+		if values, ok := pathValues(r, "kind"); ok {
+			if err := parseValues(values, ".", ".", &req.Kind); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid path parameter 'kind': " + err.Error()})
+				return
+			}
+		} else {
+			writeError(w, &StatusError{http.StatusBadRequest, "path parameter 'kind' is required"})
+			return
+		}
+		if values, ok := pathValues(r, "label"); ok {
+			if err := parseValues(values, ".", ",", &req.Label); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid path parameter 'label': " + err.Error()})
+				return
+			}
+		} else {
+			writeError(w, &StatusError{http.StatusBadRequest, "path parameter 'label' is required"})
+			return
+		}
+		if values, ok := pathValues(r, "labelExploded"); ok {
+			if err := parseValues(values, ".", ".", &req.LabelExploded); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid path parameter 'labelExploded': " + err.Error()})
+				return
+			}
+		} else {
+			writeError(w, &StatusError{http.StatusBadRequest, "path parameter 'labelExploded' is required"})
+			return
+		}
+		if values, ok := pathValues(r, "matrix"); ok {
+			if err := parseValues(values, ";matrix=", ",", &req.Matrix); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid path parameter 'matrix': " + err.Error()})
+				return
+			}
+		} else {
+			writeError(w, &StatusError{http.StatusBadRequest, "path parameter 'matrix' is required"})
+			return
+		}
+		if values, ok := pathValues(r, "matrixExploded"); ok {
+			if err := parseValues(values, ";matrixExploded=", ";matrixExploded=", &req.MatrixExploded); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid path parameter 'matrixExploded': " + err.Error()})
+				return
+			}
+		} else {
+			writeError(w, &StatusError{http.StatusBadRequest, "path parameter 'matrixExploded' is required"})
+			return
+		}
+		if values, ok := queryValues(r, "pipe"); ok {
+			if err := parseValues(values, "", "|", &req.Pipe); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid query parameter 'pipe': " + err.Error()})
+				return
+			}
+		}
+		if values, ok := cookieValues(r, "session"); ok {
+			var v string
+			if err := parseValues(values, "", ",", &v); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid cookie parameter 'session': " + err.Error()})
+				return
+			}
+			req.Session = &v
+		}
+		if values, ok := pathValues(r, "simple"); ok {
+			if err := parseValues(values, "", ",", &req.Simple); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid path parameter 'simple': " + err.Error()})
+				return
+			}
+		} else {
+			writeError(w, &StatusError{http.StatusBadRequest, "path parameter 'simple' is required"})
+			return
+		}
+		if values, ok := queryValues(r, "space"); ok {
+			if err := parseValues(values, "", " ", &req.Space); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid query parameter 'space': " + err.Error()})
+				return
+			}
+		}
+		if values, ok := queryValues(r, "spaceExploded"); ok {
+			if err := parseValues(values, "", "", &req.SpaceExploded); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid query parameter 'spaceExploded': " + err.Error()})
+				return
+			}
+		}
+		if values, ok := headerValues(r, "X-Flags"); ok {
+			if err := parseValues(values, "", ",", &req.XFlags); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid header parameter 'X-Flags': " + err.Error()})
+				return
+			}
+		}
+		resp, err := impl.StylesEcho(r.Context(), &req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if resp == nil {
+			resp = &StylesEchoResponse{}
+		}
+		if resp.Status == 0 {
+			resp.Status = 200
+		}
+		for name, values := range resp.Header {
+			w.Header()[name] = values
+		}
+		if resp.Body != nil {
+			writeJSON(w, resp.Status, resp.Body)
+			return
+		}
+		w.WriteHeader(resp.Status)
+	}
+}
+
+func pathValues(r *http.Request, name string) ([]string, bool) {
+	value, ok := mux.Vars(r)[name]
+	return []string{value}, ok
+}
+
+func queryValues(r *http.Request, name string) ([]string, bool) {
+	values, ok := r.URL.Query()[name]
+	return values, ok
+}
+
+func headerValues(r *http.Request, name string) ([]string, bool) {
+	values, ok := r.Header[http.CanonicalHeaderKey(name)]
+	return values, ok
+}
+
+func cookieValues(r *http.Request, name string) ([]string, bool) {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return nil, false
+	}
+	return []string{cookie.Value}, true
+}
+
+// parseValues converts the values of a parameter to the target, the repeated values are the items
+// of the array without a separator, otherwise they are joined by comma
+func parseValues(values []string, prefix, separator string, target interface{}) error {
+	rv := reflect.ValueOf(target).Elem()
+	if separator != "" || rv.Kind() != reflect.Slice {
+		return parseValue(strings.Join(values, ","), prefix, separator, target)
+	}
+	slice := reflect.MakeSlice(rv.Type(), len(values), len(values))
+	for i, value := range values {
+		if err := parseValue(value, prefix, separator, slice.Index(i).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	rv.Set(slice)
+	return nil
+}
+
+// parseValue converts the value of a parameter to the target,
+// the prefix of the style is removed and the arrays are split by the separator
+func parseValue(value, prefix, separator string, target interface{}) error {
+	if !strings.HasPrefix(value, prefix) {
+		return fmt.Errorf("the value must start with '%s'", prefix)
+	}
+	value = value[len(prefix):]
+	var err error
+	switch t := target.(type) {
+	case *string:
+		*t = value
+	case *int64:
+		*t, err = strconv.ParseInt(value, 10, 64)
+	case *int32:
+		var v int64
+		v, err = strconv.ParseInt(value, 10, 32)
+		*t = int32(v)
+	case *float64:
+		*t, err = strconv.ParseFloat(value, 64)
+	case *float32:
+		var v float64
+		v, err = strconv.ParseFloat(value, 32)
+		*t = float32(v)
+	case *bool:
+		*t, err = strconv.ParseBool(value)
+	default:
+		rv := reflect.ValueOf(target).Elem()
+		if rv.Kind() != reflect.Slice {
+			return json.Unmarshal([]byte(value), target)
+		}
+		parts := strings.Split(value, separator)
+		slice := reflect.MakeSlice(rv.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := parseValue(part, "", separator, slice.Index(i).Addr().Interface()); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+	}
+	return err
+}
+
+// decodeBody decodes the JSON or form body, false means the body is empty
+func decodeBody(r *http.Request, target interface{}) (bool, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err != nil {
+			return false, err
+		}
+		if len(r.PostForm) == 0 {
+			return false, nil
+		}
+		return true, decodeForm(r.PostForm, target)
+	}
+	err := json.NewDecoder(r.Body).Decode(target)
+	if err == io.EOF {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// decodeForm sets the fields of the target struct by the form values named as the JSON fields
+func decodeForm(values url.Values, target interface{}) error {
+	rv := reflect.ValueOf(target).Elem()
+	if rv.Kind() != reflect.Struct {
+		return errors.New("the form body must be an object")
+	}
+	for i := 0; i < rv.NumField(); i++ {
+		name := strings.Split(rv.Type().Field(i).Tag.Get("json"), ",")[0]
+		formValues, ok := values[name]
+		if !ok {
+			continue
+		}
+		field := rv.Field(i)
+		if field.Kind() == reflect.Ptr {
+			field.Set(reflect.New(field.Type().Elem()))
+			field = field.Elem()
+		}
+		if err := parseValue(strings.Join(formValues, ","), "", ",", field.Addr().Interface()); err != nil {
+			return fmt.Errorf("invalid field '%s': %v", name, err)
+		}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+// writeError responds by the status of the error, the errors of the server like the body over the limit
+// are written as they are
+func writeError(w http.ResponseWriter, err error) {
+	var serverErr *oas3.Error
+	if errors.As(err, &serverErr) {
+		oas3.WriteError(w, serverErr)
+		return
+	}
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		statusErr = &StatusError{http.StatusInternalServerError, err.Error()}
+	}
+	writeJSON(w, statusErr.Status, map[string]string{"message": statusErr.Message})
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SVilgelm/oas3-server/pkg/config"
+	"github.com/SVilgelm/oas3-server/pkg/oas3"
+	"github.com/SVilgelm/oas3-server/pkg/server"
+)
+
+type echo struct{}
+
+func (echo) StylesEcho(ctx context.Context, req *StylesEchoRequest) (*StylesEchoResponse, error) {
+	return &StylesEchoResponse{Body: &Echo{
+		Simple:         req.Simple,
+		Label:          req.Label,
+		Matrix:         req.Matrix,
+		LabelExploded:  req.LabelExploded,
+		MatrixExploded: req.MatrixExploded,
+		Kind:           req.Kind,
+		Form:           req.Form,
+		FormFlat:       req.FormFlat,
+		Space:          req.Space,
+		SpaceExploded:  req.SpaceExploded,
+		Pipe:           req.Pipe,
+		Flags:          req.XFlags,
+		Session:        req.Session,
+	}}, nil
+}
+
+func newServer(t *testing.T) *server.Server {
+	model, err := oas3.Load("../styles.yaml")
+	assert.NoError(t, err)
+	srv, err := server.NewServer(&config.Config{Model: model})
+	assert.NoError(t, err)
+	assert.NoError(t, Register(srv, echo{}))
+	return srv
+}
+
+func TestStyles(t *testing.T) {
+	t.Parallel()
+	srv := newServer(t)
+	serve := func(url string) (int, string) {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("X-Flags", "g,h")
+		req.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+		rec := httptest.NewRecorder()
+		srv.R.ServeHTTP(rec, req)
+		return rec.Code, rec.Body.String()
+	}
+
+	status, body := serve("/styles/items/1,2/.3,4/;matrix=5,6/.7.8/;matrixExploded=9;matrixExploded=10/.a.b" +
+		"?form=x&form=y&formFlat=x,y&space=a%20b&spaceExploded=c&spaceExploded=d&pipe=e|f")
+	assert.Equal(t, http.StatusOK, status)
+	var res Echo
+	assert.NoError(t, json.Unmarshal([]byte(body), &res))
+	session := "s1"
+	assert.Equal(t, Echo{
+		Simple:         Numbers{1, 2},
+		Label:          Numbers{3, 4},
+		Matrix:         Numbers{5, 6},
+		LabelExploded:  Numbers{7, 8},
+		MatrixExploded: Numbers{9, 10},
+		Kind:           "a.b",
+		Form:           Words{"x", "y"},
+		FormFlat:       Words{"x", "y"},
+		Space:          Words{"a", "b"},
+		SpaceExploded:  Words{"c", "d"},
+		Pipe:           Words{"e", "f"},
+		Flags:          Words{"g", "h"},
+		Session:        &session,
+	}, res)
+
+	status, body = serve("/styles/items/1/3/;matrix=5/.7/;matrixExploded=9/.a")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.JSONEq(t, `{"message":"invalid path parameter 'label': the value must start with '.'"}`, body)
+
+	status, body = serve("/styles/items/1/.3/;other=5/.7/;matrixExploded=9/.a")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.JSONEq(t, `{"message":"invalid path parameter 'matrix': the value must start with ';matrix='"}`, body)
+}
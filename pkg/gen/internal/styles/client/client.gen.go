@@ -0,0 +1,271 @@
+// Code generated by oas3-gen. DO NOT EDIT.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Echo is generated from the schema 'Echo'
+type Echo struct {
+	Flags          Words   `json:"flags,omitempty"`
+	Form           Words   `json:"form,omitempty"`
+	FormFlat       Words   `json:"formFlat,omitempty"`
+	Kind           string  `json:"kind"`
+	Label          Numbers `json:"label"`
+	LabelExploded  Numbers `json:"labelExploded"`
+	Matrix         Numbers `json:"matrix"`
+	MatrixExploded Numbers `json:"matrixExploded"`
+	Pipe           Words   `json:"pipe,omitempty"`
+	Session        *string `json:"session,omitempty"`
+	Simple         Numbers `json:"simple"`
+	Space          Words   `json:"space,omitempty"`
+	SpaceExploded  Words   `json:"spaceExploded,omitempty"`
+}
+
+// Numbers is generated from the schema 'Numbers'
+type Numbers []int64
+
+// Words is generated from the schema 'Words'
+type Words []string
+
+// StylesEchoRequest is the request of the operation 'styles.echo'
+type StylesEchoRequest struct {
+	Form           []string
+	FormFlat       []string
+	Kind           string
+	Label          []int64
+	LabelExploded  []int64
+	Matrix         []int64
+	MatrixExploded []int64
+	Pipe           []string
+	Session        *string
+	Simple         []int64
+	Space          []string
+	SpaceExploded  []string
+	XFlags         []string
+	// HTTPRequest is the original request, set by the server
+	HTTPRequest *http.Request
+}
+
+// StylesEchoResult is the result of the operation 'styles.echo'
+type StylesEchoResult struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the raw body of the response
+	Body []byte
+	// JSON200 is the body of the 200 response
+	JSON200 *Echo
+}
+
+// Client calls the operations of the API
+type Client struct {
+	// BaseURL is the URL of the server including the base path
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a Client, http.DefaultClient is used if httpClient is nil
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: httpClient,
+	}
+}
+
+// StylesEcho calls the operation 'styles.echo': Echoes the parameters serialized by all supported styles
+func (c *Client) StylesEcho(ctx context.Context, req *StylesEchoRequest) (*StylesEchoResult, error) {
+	if req == nil {
+		req = &StylesEchoRequest{}
+	}
+	path := "/items/{simple}/{label}/{matrix}/{labelExploded}/{matrixExploded}/{kind}"
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []*http.Cookie
+	if req.Form != nil {
+		addQuery(query, "form", true, "form", req.Form)
+	}
+	if req.FormFlat != nil {
+		addQuery(query, "form", false, "formFlat", req.FormFlat)
+	}
+	path = strings.Replace(path, "{kind}", formatPath("label", true, "kind", req.Kind), 1)
+	path = strings.Replace(path, "{label}", formatPath("label", false, "label", req.Label), 1)
+	path = strings.Replace(path, "{labelExploded}", formatPath("label", true, "labelExploded", req.LabelExploded), 1)
+	path = strings.Replace(path, "{matrix}", formatPath("matrix", false, "matrix", req.Matrix), 1)
+	path = strings.Replace(path, "{matrixExploded}", formatPath("matrix", true, "matrixExploded", req.MatrixExploded), 1)
+	if req.Pipe != nil {
+		addQuery(query, "pipeDelimited", false, "pipe", req.Pipe)
+	}
+	if req.Session != nil {
+		cookies = append(cookies, &http.Cookie{Name: "session", Value: strings.Join(formatValues(req.Session), ",")})
+	}
+	path = strings.Replace(path, "{simple}", formatPath("simple", false, "simple", req.Simple), 1)
+	if req.Space != nil {
+		addQuery(query, "spaceDelimited", false, "space", req.Space)
+	}
+	if req.SpaceExploded != nil {
+		addQuery(query, "spaceDelimited", true, "spaceExploded", req.SpaceExploded)
+	}
+	if req.XFlags != nil {
+		header.Set("X-Flags", strings.Join(formatValues(req.XFlags), ","))
+	}
+	var body []byte
+	resp, data, err := c.do(ctx, "GET", path, query, header, cookies, body)
+	if err != nil {
+		return nil, err
+	}
+	result := StylesEchoResult{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       data,
+	}
+	switch resp.StatusCode {
+	case 200:
+		err = decodeResult(data, &result.JSON200)
+	}
+	return &result, err
+}
+
+func (c *Client) do(
+	ctx context.Context,
+	method, path string,
+	query url.Values,
+	header http.Header,
+	cookies []*http.Cookie,
+	body []byte,
+) (*http.Response, []byte, error) {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, data, nil
+}
+
+// formatValues converts a scalar or an array to the strings
+func formatValues(value interface{}) []string {
+	rv := reflect.Indirect(reflect.ValueOf(value))
+	if rv.Kind() != reflect.Slice {
+		return []string{formatValue(rv)}
+	}
+	values := make([]string, rv.Len())
+	for i := range values {
+		values[i] = formatValue(reflect.Indirect(rv.Index(i)))
+	}
+	return values
+}
+
+func formatValue(rv reflect.Value) string {
+	switch rv.Kind() {
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+// formatPath serializes the path parameter with the simple, label or matrix style
+func formatPath(style string, explode bool, name string, value interface{}) string {
+	values := formatValues(value)
+	for i, v := range values {
+		values[i] = url.PathEscape(v)
+	}
+	switch style {
+	case "label":
+		if explode {
+			return "." + strings.Join(values, ".")
+		}
+		return "." + strings.Join(values, ",")
+	case "matrix":
+		if explode {
+			return ";" + name + "=" + strings.Join(values, ";"+name+"=")
+		}
+		return ";" + name + "=" + strings.Join(values, ",")
+	}
+	return strings.Join(values, ",")
+}
+
+// addQuery serializes the query parameter with the form, spaceDelimited or pipeDelimited style
+func addQuery(query url.Values, style string, explode bool, name string, value interface{}) {
+	values := formatValues(value)
+	switch {
+	case explode:
+		query[name] = values
+	case style == "spaceDelimited":
+		query.Set(name, strings.Join(values, " "))
+	case style == "pipeDelimited":
+		query.Set(name, strings.Join(values, "|"))
+	default:
+		query.Set(name, strings.Join(values, ","))
+	}
+}
+
+// encodeBody encodes the body by the media type
+func encodeBody(contentType string, body interface{}) ([]byte, error) {
+	if contentType != "application/x-www-form-urlencoded" {
+		return json.Marshal(body)
+	}
+	form, err := encodeForm(body)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(form.Encode()), nil
+}
+
+// encodeForm converts the fields of the struct to the form values named as the JSON fields
+func encodeForm(body interface{}) (url.Values, error) {
+	rv := reflect.Indirect(reflect.ValueOf(body))
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.New("the form body must be an object")
+	}
+	form := url.Values{}
+	for i := 0; i < rv.NumField(); i++ {
+		name := strings.Split(rv.Type().Field(i).Tag.Get("json"), ",")[0]
+		field := rv.Field(i)
+		if (field.Kind() == reflect.Ptr || field.Kind() == reflect.Slice) && field.IsNil() {
+			continue
+		}
+		form[name] = formatValues(field.Interface())
+	}
+	return form, nil
+}
+
+func decodeResult(data []byte, target interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, target)
+}
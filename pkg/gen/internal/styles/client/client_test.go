@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SVilgelm/oas3-server/pkg/config"
+	"github.com/SVilgelm/oas3-server/pkg/gen/internal/styles/api"
+	"github.com/SVilgelm/oas3-server/pkg/oas3"
+	"github.com/SVilgelm/oas3-server/pkg/server"
+)
+
+type echo struct{}
+
+func (echo) StylesEcho(ctx context.Context, req *api.StylesEchoRequest) (*api.StylesEchoResponse, error) {
+	return &api.StylesEchoResponse{Body: &api.Echo{
+		Simple:         req.Simple,
+		Label:          req.Label,
+		Matrix:         req.Matrix,
+		LabelExploded:  req.LabelExploded,
+		MatrixExploded: req.MatrixExploded,
+		Kind:           req.Kind,
+		Form:           req.Form,
+		FormFlat:       req.FormFlat,
+		Space:          req.Space,
+		SpaceExploded:  req.SpaceExploded,
+		Pipe:           req.Pipe,
+		Flags:          req.XFlags,
+		Session:        req.Session,
+	}}, nil
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+	model, err := oas3.Load("../styles.yaml")
+	assert.NoError(t, err)
+	srv, err := server.NewServer(&config.Config{Model: model})
+	assert.NoError(t, err)
+	assert.NoError(t, api.Register(srv, echo{}))
+	ts := httptest.NewServer(srv.R)
+	defer ts.Close()
+	c := NewClient(ts.URL+"/styles", ts.Client())
+
+	request := func() *StylesEchoRequest {
+		return &StylesEchoRequest{
+			Simple:         []int64{1},
+			Label:          []int64{1},
+			Matrix:         []int64{1},
+			LabelExploded:  []int64{1},
+			MatrixExploded: []int64{1},
+			Kind:           "a",
+		}
+	}
+	session := "s 1"
+	for _, tc := range []struct {
+		name  string
+		set   func(req *StylesEchoRequest)
+		check func(t *testing.T, res *Echo)
+	}{
+		{
+			name: "simple",
+			set:  func(req *StylesEchoRequest) { req.Simple = []int64{1, 2} },
+			check: func(t *testing.T, res *Echo) {
+				assert.Equal(t, Numbers{1, 2}, res.Simple)
+			},
+		},
+		{
+			name: "label",
+			set:  func(req *StylesEchoRequest) { req.Label = []int64{3, 4} },
+			check: func(t *testing.T, res *Echo) {
+				assert.Equal(t, Numbers{3, 4}, res.Label)
+			},
+		},
+		{
+			name: "label exploded",
+			set: func(req *StylesEchoRequest) {
+				req.LabelExploded = []int64{5, 6}
+				req.Kind = "b,c"
+			},
+			check: func(t *testing.T, res *Echo) {
+				assert.Equal(t, Numbers{5, 6}, res.LabelExploded)
+				assert.Equal(t, "b,c", res.Kind)
+			},
+		},
+		{
+			name: "matrix",
+			set:  func(req *StylesEchoRequest) { req.Matrix = []int64{7, 8} },
+			check: func(t *testing.T, res *Echo) {
+				assert.Equal(t, Numbers{7, 8}, res.Matrix)
+			},
+		},
+		{
+			name: "matrix exploded",
+			set:  func(req *StylesEchoRequest) { req.MatrixExploded = []int64{9, 10} },
+			check: func(t *testing.T, res *Echo) {
+				assert.Equal(t, Numbers{9, 10}, res.MatrixExploded)
+			},
+		},
+		{
+			name: "form",
+			set: func(req *StylesEchoRequest) {
+				req.Form = []string{"a,b", "c"}
+				req.FormFlat = []string{"d", "e"}
+			},
+			check: func(t *testing.T, res *Echo) {
+				assert.Equal(t, Words{"a,b", "c"}, res.Form)
+				assert.Equal(t, Words{"d", "e"}, res.FormFlat)
+			},
+		},
+		{
+			name: "spaceDelimited",
+			set: func(req *StylesEchoRequest) {
+				req.Space = []string{"a", "b"}
+				req.SpaceExploded = []string{"c d", "e"}
+			},
+			check: func(t *testing.T, res *Echo) {
+				assert.Equal(t, Words{"a", "b"}, res.Space)
+				assert.Equal(t, Words{"c d", "e"}, res.SpaceExploded)
+			},
+		},
+		{
+			name: "pipeDelimited",
+			set:  func(req *StylesEchoRequest) { req.Pipe = []string{"a", "b c"} },
+			check: func(t *testing.T, res *Echo) {
+				assert.Equal(t, Words{"a", "b c"}, res.Pipe)
+			},
+		},
+		{
+			name: "header and cookie",
+			set: func(req *StylesEchoRequest) {
+				req.XFlags = []string{"g", "h"}
+				req.Session = &session
+			},
+			check: func(t *testing.T, res *Echo) {
+				assert.Equal(t, Words{"g", "h"}, res.Flags)
+				assert.Equal(t, &session, res.Session)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req := request()
+			tc.set(req)
+			res, err := c.StylesEcho(context.Background(), req)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, 200, res.StatusCode, string(res.Body))
+			if assert.NotNil(t, res.JSON200) {
+				tc.check(t, res.JSON200)
+			}
+		})
+	}
+}
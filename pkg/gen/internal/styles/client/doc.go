@@ -0,0 +1,4 @@
+// Package client is the client generated from the specification using all supported parameter styles
+package client
+
+//go:generate go run ../../../../../cmd/oas3-gen -spec ../styles.yaml -package client -server=false -client -out client.gen.go
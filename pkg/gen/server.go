@@ -0,0 +1,266 @@
+package gen
+
+const serverTemplate = `{{range .Operations}}
+// {{.Name}}Response is the response of the operation '{{.ID}}'
+type {{.Name}}Response struct {
+	// Status is {{.Status}} if not set
+	Status int
+	Header http.Header
+{{- if .Result}}
+	Body   {{if .ResultPointer}}*{{end}}{{.Result}}
+{{- else if .ResultContentType}}
+	// ContentType is '{{.ResultContentType}}' if not set
+	ContentType string
+	Body        []byte
+{{- end}}
+}
+{{end}}
+// Interface is implemented by the handlers of the operations
+type Interface interface {
+{{- range .Operations}}
+	// {{.Name}} handles the operation '{{.ID}}'{{if .Summary}}: {{.Summary}}{{end}}
+	{{.Name}}(ctx context.Context, req *{{.Name}}Request) (*{{.Name}}Response, error)
+{{- end}}
+}
+
+// StatusError is returned by the handlers to respond with the status code
+type StatusError struct {
+	Status  int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
+// Register links the handlers of the implementation with the operations of the server
+func Register(srv *server.Server, impl Interface) error {
+{{- range .Operations}}
+	if err := srv.Handle("{{.ID}}", handle{{.Name}}(impl)); err != nil {
+		return err
+	}
+{{- end}}
+	return nil
+}
+{{range .Operations}}
+func handle{{.Name}}(impl Interface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := {{.Name}}Request{HTTPRequest: r}
+{{- range .Params}}
+		if values, ok := {{.In}}Values(r, "{{.Name}}"); ok {
+{{- if .Pointer}}
+			var v {{.Type}}
+			if err := parseValues(values, "{{.Prefix}}", "{{.Separator}}", &v); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid {{.In}} parameter '{{.Name}}': " + err.Error()})
+				return
+			}
+			req.{{.Field}} = &v
+{{- else}}
+			if err := parseValues(values, "{{.Prefix}}", "{{.Separator}}", &req.{{.Field}}); err != nil {
+				writeError(w, &StatusError{http.StatusBadRequest, "invalid {{.In}} parameter '{{.Name}}': " + err.Error()})
+				return
+			}
+{{- end}}
+		}{{if .Required}} else {
+			writeError(w, &StatusError{http.StatusBadRequest, "{{.In}} parameter '{{.Name}}' is required"})
+			return
+		}{{end}}
+{{- end}}
+{{- if .Body}}
+		var body {{.Body}}
+		if ok, err := decodeBody(r, &body); err != nil {
+			if _, tooLarge := err.(*oas3.Error); !tooLarge {
+				err = &StatusError{http.StatusBadRequest, "invalid body: " + err.Error()}
+			}
+			writeError(w, err)
+			return
+		} else if ok {
+			req.Body = {{if .BodyPointer}}&{{end}}body
+		}{{if .BodyRequired}} else {
+			writeError(w, &StatusError{http.StatusBadRequest, "body is required"})
+			return
+		}{{end}}
+{{- end}}
+		resp, err := impl.{{.Name}}(r.Context(), &req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if resp == nil {
+			resp = &{{.Name}}Response{}
+		}
+		if resp.Status == 0 {
+			resp.Status = {{.Status}}
+		}
+		for name, values := range resp.Header {
+			w.Header()[name] = values
+		}
+{{- if .Result}}
+		if resp.Body != nil {
+			writeJSON(w, resp.Status, resp.Body)
+			return
+		}
+{{- else if .ResultContentType}}
+		if resp.ContentType == "" {
+			resp.ContentType = "{{.ResultContentType}}"
+		}
+		w.Header().Set("Content-Type", resp.ContentType)
+		w.WriteHeader(resp.Status)
+		_, _ = w.Write(resp.Body)
+{{- end}}
+{{- if not .ResultContentType}}
+		w.WriteHeader(resp.Status)
+{{- end}}
+	}
+}
+{{end}}
+func pathValues(r *http.Request, name string) ([]string, bool) {
+	value, ok := mux.Vars(r)[name]
+	return []string{value}, ok
+}
+
+func queryValues(r *http.Request, name string) ([]string, bool) {
+	values, ok := r.URL.Query()[name]
+	return values, ok
+}
+
+func headerValues(r *http.Request, name string) ([]string, bool) {
+	values, ok := r.Header[http.CanonicalHeaderKey(name)]
+	return values, ok
+}
+
+func cookieValues(r *http.Request, name string) ([]string, bool) {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return nil, false
+	}
+	return []string{cookie.Value}, true
+}
+
+// parseValues converts the values of a parameter to the target, the repeated values are the items
+// of the array without a separator, otherwise they are joined by comma
+func parseValues(values []string, prefix, separator string, target interface{}) error {
+	rv := reflect.ValueOf(target).Elem()
+	if separator != "" || rv.Kind() != reflect.Slice {
+		return parseValue(strings.Join(values, ","), prefix, separator, target)
+	}
+	slice := reflect.MakeSlice(rv.Type(), len(values), len(values))
+	for i, value := range values {
+		if err := parseValue(value, prefix, separator, slice.Index(i).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	rv.Set(slice)
+	return nil
+}
+
+// parseValue converts the value of a parameter to the target,
+// the prefix of the style is removed and the arrays are split by the separator
+func parseValue(value, prefix, separator string, target interface{}) error {
+	if !strings.HasPrefix(value, prefix) {
+		return fmt.Errorf("the value must start with '%s'", prefix)
+	}
+	value = value[len(prefix):]
+	var err error
+	switch t := target.(type) {
+	case *string:
+		*t = value
+	case *int64:
+		*t, err = strconv.ParseInt(value, 10, 64)
+	case *int32:
+		var v int64
+		v, err = strconv.ParseInt(value, 10, 32)
+		*t = int32(v)
+	case *float64:
+		*t, err = strconv.ParseFloat(value, 64)
+	case *float32:
+		var v float64
+		v, err = strconv.ParseFloat(value, 32)
+		*t = float32(v)
+	case *bool:
+		*t, err = strconv.ParseBool(value)
+	default:
+		rv := reflect.ValueOf(target).Elem()
+		if rv.Kind() != reflect.Slice {
+			return json.Unmarshal([]byte(value), target)
+		}
+		parts := strings.Split(value, separator)
+		slice := reflect.MakeSlice(rv.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := parseValue(part, "", separator, slice.Index(i).Addr().Interface()); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+	}
+	return err
+}
+
+// decodeBody decodes the JSON or form body, false means the body is empty
+func decodeBody(r *http.Request, target interface{}) (bool, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err != nil {
+			return false, err
+		}
+		if len(r.PostForm) == 0 {
+			return false, nil
+		}
+		return true, decodeForm(r.PostForm, target)
+	}
+	err := json.NewDecoder(r.Body).Decode(target)
+	if err == io.EOF {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// decodeForm sets the fields of the target struct by the form values named as the JSON fields
+func decodeForm(values url.Values, target interface{}) error {
+	rv := reflect.ValueOf(target).Elem()
+	if rv.Kind() != reflect.Struct {
+		return errors.New("the form body must be an object")
+	}
+	for i := 0; i < rv.NumField(); i++ {
+		name := strings.Split(rv.Type().Field(i).Tag.Get("json"), ",")[0]
+		formValues, ok := values[name]
+		if !ok {
+			continue
+		}
+		field := rv.Field(i)
+		if field.Kind() == reflect.Ptr {
+			field.Set(reflect.New(field.Type().Elem()))
+			field = field.Elem()
+		}
+		if err := parseValue(strings.Join(formValues, ","), "", ",", field.Addr().Interface()); err != nil {
+			return fmt.Errorf("invalid field '%s': %v", name, err)
+		}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+// writeError responds by the status of the error, the errors of the server like the body over the limit
+// are written as they are
+func writeError(w http.ResponseWriter, err error) {
+	var serverErr *oas3.Error
+	if errors.As(err, &serverErr) {
+		oas3.WriteError(w, serverErr)
+		return
+	}
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		statusErr = &StatusError{http.StatusInternalServerError, err.Error()}
+	}
+	writeJSON(w, statusErr.Status, map[string]string{"message": statusErr.Message})
+}
+`
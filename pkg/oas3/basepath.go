@@ -0,0 +1,52 @@
+package oas3
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// serverURL substitutes the variables of the server URL by their default values
+func serverURL(server *openapi3.Server) (string, error) {
+	u := server.URL
+	names, err := server.ParameterNames()
+	if err != nil {
+		return "", fmt.Errorf("invalid server URL '%s': %v", server.URL, err)
+	}
+	for _, name := range names {
+		variable := server.Variables[name]
+		if variable == nil || variable.Default == nil {
+			return "", fmt.Errorf("no default value of the variable '%s' of the server URL '%s'", name, server.URL)
+		}
+		u = strings.Replace(u, "{"+name+"}", fmt.Sprint(variable.Default), -1)
+	}
+	return u, nil
+}
+
+// NormalizeBasePath makes the base path absolute without trailing slash, the root path is empty
+func NormalizeBasePath(path string) string {
+	path = strings.TrimRight(path, "/")
+	if path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
+
+// BasePath returns the path of the first server URL of the model,
+// the variables of the URL are substituted by their default values
+func BasePath(model *openapi3.Swagger) (string, error) {
+	if model == nil || len(model.Servers) == 0 || model.Servers[0] == nil {
+		return "", nil
+	}
+	raw, err := serverURL(model.Servers[0])
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid server URL '%s': %v", raw, err)
+	}
+	return NormalizeBasePath(u.Path), nil
+}
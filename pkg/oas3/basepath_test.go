@@ -0,0 +1,71 @@
+package oas3
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasePath(t *testing.T) {
+	t.Parallel()
+	for url, expected := range map[string]string{
+		"":                          "",
+		"/":                         "",
+		"http://localhost:8000/":    "",
+		"/api/v1/":                  "/api/v1",
+		"https://{host}/{base}/v1":  "/api/v1",
+		"http://example.com/api/v2": "/api/v2",
+	} {
+		model := &openapi3.Swagger{Servers: openapi3.Servers{{
+			URL: url,
+			Variables: map[string]*openapi3.ServerVariable{
+				"host": {Default: "example.com"},
+				"base": {Default: "api"},
+			},
+		}}}
+		basePath, err := BasePath(model)
+		assert.NoError(t, err, url)
+		assert.Equal(t, expected, basePath, url)
+	}
+
+	_, err := BasePath(&openapi3.Swagger{Servers: openapi3.Servers{{URL: "/{unknown}"}}})
+	assert.Error(t, err)
+	basePath, err := BasePath(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, basePath)
+}
+
+func TestRegisterOperationsBasePath(t *testing.T) {
+	t.Parallel()
+	model, err := Load("testdata/api/main.yaml", WithExternalRefs())
+	assert.NoError(t, err)
+	model.Servers = openapi3.Servers{{URL: "http://localhost/api/v1/"}}
+
+	serve := func(router *mux.Router, mapper *Mapper, url string) int {
+		router.Use(Middleware(mapper, true, false))
+		for _, route := range mapper.ByID("items.get").Routes {
+			route.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		return rec.Code
+	}
+
+	router := mux.NewRouter()
+	mapper, err := RegisterOperations(model, router)
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v1", mapper.BasePath())
+	assert.Equal(t, http.StatusOK, serve(router, mapper, "/api/v1/items/1"))
+	assert.Equal(t, http.StatusBadRequest, serve(router, mapper, "/api/v1/items/abc"))
+	assert.Equal(t, http.StatusNotFound, serve(router, mapper, "/items/1"))
+
+	router = mux.NewRouter()
+	mapper, err = RegisterOperations(model, router, WithBasePath("/"))
+	assert.NoError(t, err)
+	assert.Empty(t, mapper.BasePath())
+	assert.Equal(t, http.StatusOK, serve(router, mapper, "/items/1"))
+}
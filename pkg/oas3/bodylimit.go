@@ -0,0 +1,206 @@
+package oas3
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// maxHintDepth limits the nesting of the schemas used for the size hints
+const maxHintDepth = 8
+
+// getIntExt returns the value of the integer extension and if it is set
+func getIntExt(name string, extensions map[string]interface{}) (int64, bool, error) {
+	raw, ok := extensions[name].(json.RawMessage)
+	if !ok {
+		return 0, false, nil
+	}
+	var res int64
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return 0, false, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return res, true, nil
+}
+
+// schemaSize returns the maximal size of the compact encoding of a value of the schema,
+// 0 if the size is not limited by maxLength, maxItems, enum or the type
+func schemaSize(ref *openapi3.SchemaRef, quoted bool, depth int) int64 {
+	if ref == nil || ref.Value == nil || depth > maxHintDepth {
+		return 0
+	}
+	schema := ref.Value
+	if len(schema.Enum) > 0 {
+		var size int64
+		for _, value := range schema.Enum {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return 0
+			}
+			if int64(len(data)) > size {
+				size = int64(len(data))
+			}
+		}
+		return size
+	}
+	switch schema.Type {
+	case "string":
+		if schema.MaxLength == nil {
+			return 0
+		}
+		if quoted {
+			// a character is escaped by up to 6 bytes, e.g. \u0000
+			return 6*int64(*schema.MaxLength) + 2
+		}
+		return 4 * int64(*schema.MaxLength)
+	case "integer", "number":
+		return 32
+	case "boolean":
+		return 5
+	case "array":
+		item := schemaSize(schema.Items, true, depth+1)
+		if schema.MaxItems == nil || item == 0 {
+			return 0
+		}
+		return 2 + int64(*schema.MaxItems)*(item+1)
+	case "object":
+		if schema.AdditionalPropertiesAllowed == nil || *schema.AdditionalPropertiesAllowed {
+			return 0
+		}
+		size := int64(2)
+		for name, prop := range schema.Properties {
+			value := schemaSize(prop, true, depth+1)
+			if value == 0 {
+				return 0
+			}
+			size += 6*int64(len(name)) + 3 + value + 1
+		}
+		return size
+	}
+	return 0
+}
+
+// bodySizeHint returns the limit of the request body derived from the schemas of the JSON and text bodies,
+// the limit is twice the maximal size of the compact encoding plus 1KB to allow the formatting,
+// 0 if any media type of the body is not limited
+func bodySizeHint(body *openapi3.RequestBodyRef) int64 {
+	if body == nil || body.Value == nil || len(body.Value.Content) == 0 {
+		return 0
+	}
+	var hint int64
+	for contentType, media := range body.Value.Content {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || media == nil || (mediaType != "application/json" && mediaType != "text/plain") {
+			return 0
+		}
+		size := schemaSize(media.Schema, mediaType == "application/json", 0)
+		if size == 0 {
+			return 0
+		}
+		if size > hint {
+			hint = size
+		}
+	}
+	return 2*hint + 1024
+}
+
+// maxBodySize returns the limit of the request body of the operation: the x-max-body-size extension
+// as it is or the default limit tightened by the hints of the schemas, 0 means no limit
+func maxBodySize(operation *openapi3.Operation, def int64) (int64, error) {
+	value, ok, err := getIntExt("x-max-body-size", operation.Extensions)
+	if err != nil || ok {
+		return value, err
+	}
+	limit := def
+	if hint := bodySizeHint(operation.RequestBody); hint > 0 && (limit <= 0 || hint < limit) {
+		limit = hint
+	}
+	return limit, nil
+}
+
+func bodyTooLarge(operationID string, limit int64) *Error {
+	return &Error{
+		Status:    http.StatusRequestEntityTooLarge,
+		Code:      "body_too_large",
+		Message:   fmt.Sprintf("the request body exceeds %d bytes", limit),
+		Operation: operationID,
+	}
+}
+
+// limitedBody is the request body limited by http.MaxBytesReader,
+// reading over the limit fails by the *Error with the 413 status and marks the body as exceeded
+type limitedBody struct {
+	io.ReadCloser
+	err      *Error
+	limit    int64
+	read     int64
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if err != nil && err != io.EOF && b.read >= b.limit {
+		b.exceeded = true
+		return n, b.err
+	}
+	return n, err
+}
+
+// limitBody responds by 413 if the declared length of the body exceeds the limit
+// or limits the reading of the body otherwise, false is returned if the request is rejected,
+// the limited body is returned to check it after the handler
+func limitBody(w http.ResponseWriter, r *http.Request, operationID string, limit int64) (*limitedBody, bool) {
+	if limit <= 0 || r.Body == nil || r.Body == http.NoBody {
+		return nil, true
+	}
+	if r.ContentLength > limit {
+		WriteError(w, bodyTooLarge(operationID, limit))
+		return nil, false
+	}
+	body := &limitedBody{
+		ReadCloser: http.MaxBytesReader(w, r.Body, limit),
+		err:        bodyTooLarge(operationID, limit),
+		limit:      limit,
+	}
+	r.Body = body
+	return body, true
+}
+
+// startWriter tracks if the response is started
+type startWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (sw *startWriter) WriteHeader(status int) {
+	sw.started = true
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+func (sw *startWriter) Write(data []byte) (int, error) {
+	sw.started = true
+	return sw.ResponseWriter.Write(data)
+}
+
+func (sw *startWriter) Flush() {
+	sw.started = true
+	if f, ok := sw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// respondTooLarge responds by 413 if the handler has read the body over the limit
+// and returned without starting the response, e.g. after ignoring the error of the body
+func respondTooLarge(next http.Handler, body *limitedBody) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := startWriter{ResponseWriter: w}
+		next.ServeHTTP(&sw, r)
+		if body.exceeded && !sw.started {
+			WriteError(w, body.err)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package oas3
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaSize(t *testing.T) {
+	t.Parallel()
+	ten := uint64(10)
+	two := uint64(2)
+	closed := false
+	for _, tc := range []struct {
+		schema   *openapi3.Schema
+		quoted   bool
+		expected int64
+	}{
+		{&openapi3.Schema{Type: "string"}, true, 0},
+		{&openapi3.Schema{Type: "string", MaxLength: &ten}, true, 62},
+		{&openapi3.Schema{Type: "string", MaxLength: &ten}, false, 40},
+		{&openapi3.Schema{Type: "string", Enum: []interface{}{"a", "long"}}, true, 6},
+		{&openapi3.Schema{Type: "boolean"}, true, 5},
+		{&openapi3.Schema{Type: "array", Items: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "boolean"})}, true, 0},
+		{&openapi3.Schema{Type: "array", MaxItems: &two, Items: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "boolean"})}, true, 14},
+		{&openapi3.Schema{Type: "object", Properties: map[string]*openapi3.SchemaRef{
+			"ok": openapi3.NewSchemaRef("", &openapi3.Schema{Type: "boolean"}),
+		}}, true, 0},
+		{&openapi3.Schema{Type: "object", AdditionalPropertiesAllowed: &closed, Properties: map[string]*openapi3.SchemaRef{
+			"ok": openapi3.NewSchemaRef("", &openapi3.Schema{Type: "boolean"}),
+		}}, true, 23},
+	} {
+		assert.Equal(t, tc.expected, schemaSize(openapi3.NewSchemaRef("", tc.schema), tc.quoted, 0), tc.schema)
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	t.Parallel()
+	model, err := Load("testdata/limits/limits.yaml")
+	assert.NoError(t, err)
+
+	newRouter := func(opts ...Option) *mux.Router {
+		router := mux.NewRouter()
+		mapper, err := RegisterOperations(model, router, opts...)
+		assert.NoError(t, err)
+		router.Use(Middleware(mapper, true, false))
+		for _, id := range []string{"notes.create", "uploads.create", "tags.put", "labels.put"} {
+			mapper.ByID(id).Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, err := ioutil.ReadAll(r.Body)
+				if e, ok := err.(*Error); ok {
+					WriteError(w, e)
+					return
+				}
+				assert.NoError(t, err)
+				_, _ = w.Write(data)
+			}))
+		}
+		return router
+	}
+	router := newRouter(WithMaxBodySize(64))
+	serve := func(method, path string, body io.Reader) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(method, path, body))
+		return rec
+	}
+	assertTooLarge := func(rec *httptest.ResponseRecorder, operationID string, limit int64) {
+		t.Helper()
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+		var e Error
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &e))
+		assert.Equal(t, *bodyTooLarge(operationID, limit), e)
+	}
+
+	rec := serve(http.MethodPost, "/notes", strings.NewReader(`{"text":"short"}`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assertTooLarge(serve(http.MethodPost, "/notes", strings.NewReader(strings.Repeat("a", 65))), "notes.create", 64)
+	// the length of the body is unknown, the limit is checked by reading
+	body := struct{ io.Reader }{strings.NewReader(strings.Repeat("a", 65))}
+	assertTooLarge(serve(http.MethodPost, "/notes", body), "notes.create", 64)
+
+	rec = serve(http.MethodPost, "/uploads", strings.NewReader(strings.Repeat("a", 4096)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 4096, rec.Body.Len())
+
+	assertTooLarge(serve(http.MethodPut, "/tags", strings.NewReader(strings.Repeat(" ", 65))), "tags.put", 64)
+	rec = serve(http.MethodPost, "/notes", strings.NewReader(strings.Repeat("a", 4096)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	router = newRouter()
+	rec = serve(http.MethodPost, "/notes", strings.NewReader(strings.Repeat("a", 4096)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	// 2 * (2 + 2 * (62 + 1)) + 1024
+	limit := int64(1280)
+	rec = serve(http.MethodPut, "/tags", strings.NewReader(`["a", "b"]`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assertTooLarge(serve(http.MethodPut, "/tags", strings.NewReader(strings.Repeat(" ", 1281))), "tags.put", limit)
+	// the extension is not tightened by the hint of the schema
+	rec = serve(http.MethodPut, "/labels", strings.NewReader(strings.Repeat(" ", 4096)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assertTooLarge(serve(http.MethodPut, "/labels", strings.NewReader(strings.Repeat(" ", 4097))), "labels.put", 4096)
+
+	// the handlers ignoring the error of the body without responding are answered by 413
+	router = mux.NewRouter()
+	mapper, err := RegisterOperations(model, router, WithMaxBodySize(64))
+	assert.NoError(t, err)
+	router.Use(Middleware(mapper, false, false))
+	mapper.ByID("notes.create").Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ioutil.ReadAll(r.Body)
+	}))
+	mapper.ByID("tags.put").Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	body = struct{ io.Reader }{strings.NewReader(strings.Repeat("a", 65))}
+	assertTooLarge(serve(http.MethodPost, "/notes", body), "notes.create", 64)
+	body = struct{ io.Reader }{strings.NewReader(strings.Repeat("a", 64))}
+	rec = serve(http.MethodPost, "/notes", body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+	body = struct{ io.Reader }{strings.NewReader(strings.Repeat(" ", 65))}
+	rec = serve(http.MethodPut, "/tags", body)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "the started response is not changed")
+}
@@ -0,0 +1,273 @@
+package oas3
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+// DefaultCompressMinSize is the minimal size of the compressed responses used if the size is not set
+const DefaultCompressMinSize = 1024
+
+// incompressibleTypes are the media types already compressed by their formats
+var incompressibleTypes = map[string]bool{
+	"application/gzip":             true,
+	"application/x-gzip":           true,
+	"application/zip":              true,
+	"application/x-bzip2":          true,
+	"application/x-7z-compressed":  true,
+	"application/x-rar-compressed": true,
+	"application/x-xz":             true,
+	"application/zstd":             true,
+	"application/pdf":              true,
+	"application/octet-stream":     true,
+	"font/woff":                    true,
+	"font/woff2":                   true,
+}
+
+// compressible checks if the responses of the media type are worth compressing
+func compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case incompressibleTypes[mediaType]:
+		return false
+	case mediaType == "image/svg+xml":
+		return true
+	case strings.HasPrefix(mediaType, "image/"),
+		strings.HasPrefix(mediaType, "video/"),
+		strings.HasPrefix(mediaType, "audio/"):
+		return false
+	}
+	return true
+}
+
+// compressEncoding selects the supported encoding by the Accept-Encoding header, gzip is preferred
+func compressEncoding(header string) string {
+	for _, a := range parseAccept(header) {
+		if a.quality <= 0 {
+			continue
+		}
+		switch a.value {
+		case "gzip", "deflate":
+			return a.value
+		case "*":
+			switch {
+			case acceptsEncoding(header, "gzip"):
+				return "gzip"
+			case acceptsEncoding(header, "deflate"):
+				return "deflate"
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// addVary adds the request header to the Vary header unless it is there already
+func addVary(header http.Header, name string) {
+	for _, value := range header["Vary"] {
+		for _, v := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), name) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", name)
+}
+
+// encoder is a compressing writer reset for every response
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+type compressor struct {
+	minSize int
+	pools   map[string]*sync.Pool
+	mapper  *Mapper
+	next    http.Handler
+}
+
+func newPool(newEncoder func() encoder) *sync.Pool {
+	return &sync.Pool{New: func() interface{} { return newEncoder() }}
+}
+
+// compressWriter collects the beginning of the body to decide if the response is compressed,
+// the body is passed through the encoder after the decision
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+	pool     *sync.Pool
+	enc      encoder
+	buf      []byte
+	status   int
+	decided  bool
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.decided || w.status != 0 {
+		return
+	}
+	w.status = status
+}
+
+func (w *compressWriter) Write(body []byte) (int, error) {
+	if !w.decided {
+		w.buf = append(w.buf, body...)
+		if len(w.buf) < w.minSize {
+			return len(body), nil
+		}
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+		return len(body), nil
+	}
+	if w.enc != nil {
+		return w.enc.Write(body)
+	}
+	return w.ResponseWriter.Write(body)
+}
+
+// decide compresses the response if it has a body of a compressible type and it is not encoded yet,
+// then sends the headers and the collected part of the body
+func (w *compressWriter) decide() error {
+	w.decided = true
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	header := w.Header()
+	addVary(header, "Accept-Encoding")
+	if header.Get("Content-Type") == "" && len(w.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+	if len(w.buf) >= w.minSize &&
+		w.status != http.StatusNoContent && w.status != http.StatusNotModified &&
+		w.status != http.StatusPartialContent && w.status >= http.StatusOK &&
+		header.Get("Content-Encoding") == "" &&
+		compressible(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		w.enc = w.pool.Get().(encoder)
+		w.enc.Reset(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if len(w.buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(w.buf)
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf)
+	}
+	w.buf = nil
+	return err
+}
+
+// Flush sends the collected part of the body, the response is compressed only if it is big enough
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return
+		}
+	}
+	if w.enc != nil {
+		if err := w.enc.Flush(); err != nil {
+			return
+		}
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *compressWriter) close() error {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return err
+		}
+	}
+	if w.enc == nil {
+		return nil
+	}
+	err := w.enc.Close()
+	w.enc.Reset(nil)
+	w.pool.Put(w.enc)
+	w.enc = nil
+	return err
+}
+
+func (c *compressor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route := mux.CurrentRoute(r)
+	if item := c.mapper.ByRoute(route); item == nil || item.meta[route].noCompress {
+		c.next.ServeHTTP(w, r)
+		return
+	}
+	encoding := compressEncoding(r.Header.Get("Accept-Encoding"))
+	if encoding == "" || r.Method == http.MethodHead {
+		addVary(w.Header(), "Accept-Encoding")
+		c.next.ServeHTTP(w, r)
+		return
+	}
+	cw := compressWriter{
+		ResponseWriter: w,
+		encoding:       encoding,
+		minSize:        c.minSize,
+		pool:           c.pools[encoding],
+	}
+	defer func() {
+		if err := cw.close(); err != nil {
+			log.Println("Cannot compress the response:", err.Error())
+		}
+	}()
+	c.next.ServeHTTP(&cw, r)
+}
+
+// Compress is a middleware to compress the responses by gzip or deflate negotiated by the Accept-Encoding header.
+// The responses smaller than minSize, of the compressed media types and the already encoded ones are sent as is,
+// the operations with "x-compress: false" are not compressed.
+// The level is from -2 (Huffman only) to 9 (best compression), 0 means the default level.
+// The Middleware must be used after Compress, the buffered responses are compressed at once.
+func Compress(mapper *Mapper, minSize, level int) (mux.MiddlewareFunc, error) {
+	if minSize <= 0 {
+		minSize = DefaultCompressMinSize
+	}
+	if level == 0 {
+		level = flate.DefaultCompression
+	}
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		return nil, fmt.Errorf("invalid compression level: %d", level)
+	}
+	pools := map[string]*sync.Pool{
+		"gzip": newPool(func() encoder {
+			zw, _ := gzip.NewWriterLevel(nil, level)
+			return zw
+		}),
+		"deflate": newPool(func() encoder {
+			zw, _ := zlib.NewWriterLevel(nil, level)
+			return zw
+		}),
+	}
+	return func(next http.Handler) http.Handler {
+		return &compressor{
+			minSize: minSize,
+			pools:   pools,
+			mapper:  mapper,
+			next:    next,
+		}
+	}, nil
+}
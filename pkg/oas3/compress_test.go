@@ -0,0 +1,146 @@
+package oas3
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressEncoding(t *testing.T) {
+	t.Parallel()
+	for header, expected := range map[string]string{
+		"":                         "",
+		"gzip":                     "gzip",
+		"deflate":                  "deflate",
+		"deflate, gzip":            "deflate",
+		"deflate;q=0.5, gzip":      "gzip",
+		"br":                       "",
+		"*":                        "gzip",
+		"gzip;q=0, *":              "deflate",
+		"identity, deflate;q=0.1":  "deflate",
+		"gzip;q=0, deflate;q=0, *": "",
+	} {
+		assert.Equal(t, expected, compressEncoding(header), header)
+	}
+}
+
+func TestCompressible(t *testing.T) {
+	t.Parallel()
+	for contentType, expected := range map[string]bool{
+		"application/json":         true,
+		"text/html; charset=utf-8": true,
+		"image/svg+xml":            true,
+		"image/png":                false,
+		"video/mp4":                false,
+		"application/zip":          false,
+		"application/gzip":         false,
+		"":                         false,
+	} {
+		assert.Equal(t, expected, compressible(contentType), contentType)
+	}
+}
+
+func TestCompress(t *testing.T) {
+	t.Parallel()
+	_, err := Compress(nil, 0, 10)
+	assert.EqualError(t, err, "invalid compression level: 10")
+
+	model, err := Load("testdata/compress/compress.yaml")
+	assert.NoError(t, err)
+	big := strings.Repeat(`{"name":"value"},`, 100)
+
+	for _, validation := range []bool{false, true} {
+		router := mux.NewRouter()
+		mapper, err := RegisterOperations(model, router)
+		assert.NoError(t, err)
+		compress, err := Compress(mapper, 512, 0)
+		assert.NoError(t, err)
+		router.Use(compress, Middleware(mapper, false, validation))
+
+		var body, contentType, encoding string
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			if contentType != "" {
+				w.Header().Set("Content-Type", contentType)
+			}
+			if encoding != "" {
+				w.Header().Set("Content-Encoding", encoding)
+			}
+			w.WriteHeader(http.StatusCreated)
+			// several writes cross the minimal size
+			for i := 0; i < len(body); i += 100 {
+				end := i + 100
+				if end > len(body) {
+					end = len(body)
+				}
+				_, _ = w.Write([]byte(body[i:end]))
+			}
+		}
+		mapper.ByID("data.get").Handle(http.HandlerFunc(handler))
+		mapper.ByID("export.get").Handle(http.HandlerFunc(handler))
+
+		serve := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			return rec
+		}
+		decode := func(r io.Reader, err error) string {
+			assert.NoError(t, err)
+			data, err := ioutil.ReadAll(r)
+			assert.NoError(t, err)
+			return string(data)
+		}
+
+		body, contentType, encoding = big, "application/json", ""
+		rec := serve("/data", "gzip")
+		assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+		assert.Equal(t, big, decode(gzip.NewReader(rec.Body)))
+
+		rec = serve("/data", "deflate")
+		assert.Equal(t, "deflate", rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, big, decode(zlib.NewReader(rec.Body)))
+
+		rec = serve("/data", "")
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+		assert.Equal(t, big, rec.Body.String())
+
+		rec = serve("/export", "gzip")
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Empty(t, rec.Header().Get("Vary"))
+		assert.Equal(t, big, rec.Body.String())
+
+		contentType = ""
+		rec = serve("/data", "gzip")
+		assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+
+		contentType = "image/png"
+		rec = serve("/data", "gzip")
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, big, rec.Body.String())
+
+		contentType, encoding = "application/json", "br"
+		rec = serve("/data", "gzip")
+		assert.Equal(t, "br", rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, big, rec.Body.String())
+
+		body, encoding = `{"name":"value"}`, ""
+		rec = serve("/data", "gzip")
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, body, rec.Body.String())
+	}
+}
@@ -0,0 +1,493 @@
+package oas3
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"log"
+	"net/http"
+)
+
+// consoleTemplate is the page of the console, the model is inlined as JSON,
+// so the console works without any other requests and without external assets
+const consoleTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} - Developer Console</title>
+<style>{{.Style}}</style>
+</head>
+<body>
+<header id="info"></header>
+<main id="operations"></main>
+<script>
+window.OAS3_SPEC = {{.Model}};
+window.OAS3_BASE_PATH = {{.BasePath}};
+</script>
+<script>{{.Script}}</script>
+</body>
+</html>
+`
+
+const consoleStyle = `
+body { margin: 0; font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #222; background: #fafafa; }
+header { padding: 16px 24px; background: #fff; border-bottom: 1px solid #ddd; }
+header h1 { margin: 0 0 4px; font-size: 24px; }
+header .version { color: #666; font-size: 14px; margin-left: 8px; }
+main { padding: 16px 24px; }
+h2 { font-size: 20px; border-bottom: 1px solid #ddd; padding-bottom: 4px; }
+h4 { margin: 12px 0 6px; }
+details.operation { background: #fff; border: 1px solid #ddd; border-radius: 4px; margin: 8px 0; }
+details.operation > summary { cursor: pointer; padding: 8px; font-family: monospace; font-size: 14px; }
+details.operation > div { padding: 0 12px 12px; }
+.method { display: inline-block; min-width: 64px; text-align: center; color: #fff; border-radius: 3px; padding: 2px 4px; margin-right: 8px; text-transform: uppercase; }
+.method.get { background: #2b7bb9; } .method.post { background: #2f9e44; } .method.put { background: #e8890c; }
+.method.patch { background: #7048e8; } .method.delete { background: #e03131; } .method.head, .method.options, .method.trace { background: #666; }
+.summary { color: #555; margin-left: 8px; font-family: sans-serif; }
+.description { white-space: pre-wrap; }
+table { border-collapse: collapse; width: 100%; margin: 4px 0; }
+th, td { text-align: left; border-bottom: 1px solid #eee; padding: 4px 8px; vertical-align: top; font-size: 14px; }
+td input { width: 100%; box-sizing: border-box; }
+.required { color: #e03131; }
+code, pre, textarea { font-family: monospace; font-size: 13px; }
+pre { background: #f4f4f4; padding: 8px; overflow: auto; max-height: 400px; margin: 4px 0; }
+textarea { width: 100%; box-sizing: border-box; min-height: 120px; }
+button { padding: 6px 16px; margin: 8px 0; cursor: pointer; }
+.status { font-weight: bold; }
+`
+
+const consoleScript = `
+(function () {
+  "use strict";
+  var spec = window.OAS3_SPEC || {};
+  var basePath = window.OAS3_BASE_PATH || "";
+  var methods = ["get", "put", "post", "delete", "options", "head", "patch", "trace"];
+  var maxDepth = 8;
+
+  function el(tag, attrs, children) {
+    var node = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (name) {
+      if (name === "text") {
+        node.textContent = attrs[name];
+      } else {
+        node.setAttribute(name, attrs[name]);
+      }
+    });
+    (children || []).forEach(function (child) {
+      if (child) {
+        node.appendChild(typeof child === "string" ? document.createTextNode(child) : child);
+      }
+    });
+    return node;
+  }
+
+  function resolve(obj) {
+    var seen = 0;
+    while (obj && typeof obj.$ref === "string" && obj.$ref.indexOf("#/") === 0 && seen < maxDepth) {
+      var target = spec;
+      obj.$ref.substring(2).split("/").forEach(function (part) {
+        part = decodeURIComponent(part).replace(/~1/g, "/").replace(/~0/g, "~");
+        target = target ? target[part] : undefined;
+      });
+      obj = target;
+      seen++;
+    }
+    return obj;
+  }
+
+  function refName(obj) {
+    return obj && typeof obj.$ref === "string" ? obj.$ref.substring(obj.$ref.lastIndexOf("/") + 1) : "";
+  }
+
+  function typeName(schema) {
+    var name = refName(schema);
+    schema = resolve(schema) || {};
+    if (name) {
+      return name;
+    }
+    if (schema.type === "array") {
+      return typeName(schema.items) + "[]";
+    }
+    var composed = schema.allOf || schema.oneOf || schema.anyOf;
+    if (!schema.type && composed) {
+      return composed.map(typeName).join(schema.allOf ? " & " : " | ");
+    }
+    var type = schema.type || "object";
+    if (schema.format) {
+      type += " (" + schema.format + ")";
+    }
+    if (schema["enum"]) {
+      type += " [" + schema["enum"].map(function (v) { return JSON.stringify(v); }).join(", ") + "]";
+    }
+    return type;
+  }
+
+  function exampleOf(schema, depth) {
+    schema = resolve(schema);
+    if (!schema || depth > maxDepth) {
+      return undefined;
+    }
+    if (schema.example !== undefined) {
+      return schema.example;
+    }
+    if (schema["default"] !== undefined) {
+      return schema["default"];
+    }
+    if (schema["enum"] && schema["enum"].length) {
+      return schema["enum"][0];
+    }
+    var composed = schema.allOf || schema.oneOf || schema.anyOf;
+    if (!schema.type && !schema.properties && composed && composed.length) {
+      return exampleOf(composed[0], depth + 1);
+    }
+    switch (schema.type) {
+      case "string":
+        return schema.format ? schema.format : "string";
+      case "integer":
+      case "number":
+        return schema.minimum !== undefined ? schema.minimum : 0;
+      case "boolean":
+        return false;
+      case "array":
+        var item = exampleOf(schema.items, depth + 1);
+        return item === undefined ? [] : [item];
+    }
+    var obj = {};
+    Object.keys(schema.properties || {}).forEach(function (name) {
+      var prop = resolve(schema.properties[name]);
+      if (prop && !prop.writeOnly) {
+        var value = exampleOf(prop, depth + 1);
+        if (value !== undefined) {
+          obj[name] = value;
+        }
+      }
+    });
+    return obj;
+  }
+
+  function mediaExample(media) {
+    if (!media) {
+      return undefined;
+    }
+    if (media.example !== undefined) {
+      return media.example;
+    }
+    var names = Object.keys(media.examples || {}).sort();
+    for (var i = 0; i < names.length; i++) {
+      var example = resolve(media.examples[names[i]]);
+      if (example && example.value !== undefined) {
+        return example.value;
+      }
+    }
+    return exampleOf(media.schema, 0);
+  }
+
+  function format(value) {
+    return typeof value === "string" ? value : JSON.stringify(value, null, 2);
+  }
+
+  function schemaView(schema, depth) {
+    schema = resolve(schema);
+    if (!schema || depth > 2) {
+      return null;
+    }
+    if (schema.type === "array") {
+      return schemaView(schema.items, depth);
+    }
+    var names = Object.keys(schema.properties || {});
+    if (!names.length) {
+      return null;
+    }
+    var required = schema.required || [];
+    var rows = names.map(function (name) {
+      var prop = schema.properties[name];
+      var value = resolve(prop) || {};
+      return el("tr", {}, [
+        el("td", {}, [el("code", {text: name}), required.indexOf(name) >= 0 ? el("span", {"class": "required", text: " *"}) : null]),
+        el("td", {text: typeName(prop)}),
+        el("td", {}, [el("div", {"class": "description", text: value.description || ""}), schemaView(prop, depth + 1)])
+      ]);
+    });
+    return el("table", {}, [
+      el("thead", {}, [el("tr", {}, [el("th", {text: "Property"}), el("th", {text: "Type"}), el("th", {text: "Description"})])]),
+      el("tbody", {}, rows)
+    ]);
+  }
+
+  function mediaView(content) {
+    return Object.keys(content || {}).sort().map(function (contentType) {
+      var media = content[contentType] || {};
+      var example = mediaExample(media);
+      return el("div", {}, [
+        el("div", {}, [el("code", {text: contentType}), media.schema ? " " + typeName(media.schema) : ""]),
+        schemaView(media.schema, 0),
+        example !== undefined ? el("pre", {text: format(example)}) : null
+      ]);
+    });
+  }
+
+  function parameters(pathItem, operation) {
+    var params = [];
+    (pathItem.parameters || []).concat(operation.parameters || []).forEach(function (ref) {
+      var param = resolve(ref);
+      if (!param) {
+        return;
+      }
+      params = params.filter(function (p) {
+        return p["in"] !== param["in"] || p.name !== param.name;
+      });
+      params.push(param);
+    });
+    return params;
+  }
+
+  function paramExample(param) {
+    var value = param.example !== undefined ? param.example : mediaExample({examples: param.examples});
+    if (value === undefined) {
+      value = exampleOf(param.schema, 0);
+    }
+    if (value === undefined || value === null) {
+      return "";
+    }
+    return Array.isArray(value) ? value.join(",") : typeof value === "object" ? JSON.stringify(value) : String(value);
+  }
+
+  function send(method, path, params, inputs, bodyContentType, body, output) {
+    var query = [];
+    var headers = {};
+    var url = path;
+    params.forEach(function (param, i) {
+      var value = inputs[i].value;
+      if (value === "") {
+        return;
+      }
+      switch (param["in"]) {
+        case "path":
+          url = url.replace("{" + param.name + "}", encodeURIComponent(value));
+          break;
+        case "query":
+          var schema = resolve(param.schema) || {};
+          var values = schema.type === "array" && param.explode !== false ? value.split(",") : [value];
+          values.forEach(function (v) {
+            query.push(encodeURIComponent(param.name) + "=" + encodeURIComponent(v));
+          });
+          break;
+        case "header":
+          headers[param.name] = value;
+          break;
+        case "cookie":
+          document.cookie = encodeURIComponent(param.name) + "=" + encodeURIComponent(value) + "; path=/";
+          break;
+      }
+    });
+    var init = {method: method.toUpperCase(), headers: headers, credentials: "same-origin"};
+    if (body && body.value !== "") {
+      headers["Content-Type"] = bodyContentType;
+      init.body = body.value;
+    }
+    url = basePath + url + (query.length ? "?" + query.join("&") : "");
+    output.textContent = init.method + " " + url + "\n...";
+    var started = Date.now();
+    fetch(url, init).then(function (resp) {
+      return resp.text().then(function (text) {
+        var lines = [];
+        resp.headers.forEach(function (value, name) {
+          lines.push(name + ": " + value);
+        });
+        try {
+          text = JSON.stringify(JSON.parse(text), null, 2);
+        } catch (e) {
+          // not JSON, shown as is
+        }
+        output.textContent = "";
+        output.appendChild(el("span", {"class": "status", text: resp.status + " " + resp.statusText}));
+        output.appendChild(document.createTextNode(
+          " (" + (Date.now() - started) + " ms)\n" + init.method + " " + url + "\n\n" + lines.join("\n") + "\n\n" + text
+        ));
+      });
+    }).catch(function (err) {
+      output.textContent = init.method + " " + url + "\n\n" + err;
+    });
+  }
+
+  function tryIt(method, path, params, requestBody) {
+    var inputs = [];
+    var rows = params.map(function (param) {
+      var input = el("input", {type: "text", value: paramExample(param), placeholder: typeName(param.schema)});
+      inputs.push(input);
+      return el("tr", {}, [el("td", {}, [el("code", {text: param["in"] + ":" + param.name})]), el("td", {}, [input])]);
+    });
+    var body = null;
+    var bodyContentType = "";
+    var content = (resolve(requestBody) || {}).content || {};
+    var contentTypes = Object.keys(content).sort();
+    if (contentTypes.length) {
+      bodyContentType = content["application/json"] ? "application/json" : contentTypes[0];
+      var example = mediaExample(content[bodyContentType]);
+      body = el("textarea", {}, []);
+      body.value = example === undefined ? "" : format(example);
+    }
+    var output = el("pre", {text: ""});
+    var button = el("button", {type: "button", text: "Send"});
+    button.addEventListener("click", function () {
+      send(method, path, params, inputs, bodyContentType, body, output);
+    });
+    return el("div", {}, [
+      el("h4", {text: "Try it out"}),
+      rows.length ? el("table", {}, [el("tbody", {}, rows)]) : null,
+      body ? el("div", {}, [el("code", {text: bodyContentType}), body]) : null,
+      button,
+      output
+    ]);
+  }
+
+  function operationView(method, path, pathItem, operation) {
+    var params = parameters(pathItem, operation);
+    var paramRows = params.map(function (param) {
+      return el("tr", {}, [
+        el("td", {}, [el("code", {text: param.name}), param.required ? el("span", {"class": "required", text: " *"}) : null]),
+        el("td", {text: param["in"]}),
+        el("td", {text: typeName(param.schema)}),
+        el("td", {"class": "description", text: param.description || ""})
+      ]);
+    });
+    var requestBody = resolve(operation.requestBody);
+    var codes = Object.keys(operation.responses || {}).sort();
+    return el("details", {"class": "operation", id: operation.operationId || method + path}, [
+      el("summary", {}, [
+        el("span", {"class": "method " + method, text: method}),
+        el("span", {text: basePath + path}),
+        el("span", {"class": "summary", text: operation.summary || ""})
+      ]),
+      el("div", {}, [
+        operation.operationId ? el("p", {}, ["Operation: ", el("code", {text: operation.operationId})]) : null,
+        operation.description ? el("p", {"class": "description", text: operation.description}) : null,
+        paramRows.length ? el("h4", {text: "Parameters"}) : null,
+        paramRows.length ? el("table", {}, [
+          el("thead", {}, [el("tr", {}, [el("th", {text: "Name"}), el("th", {text: "In"}), el("th", {text: "Type"}), el("th", {text: "Description"})])]),
+          el("tbody", {}, paramRows)
+        ]) : null,
+        requestBody ? el("h4", {text: "Request body" + (requestBody.required ? " (required)" : "")}) : null,
+        requestBody && requestBody.description ? el("p", {"class": "description", text: requestBody.description}) : null,
+        requestBody ? el("div", {}, mediaView(requestBody.content)) : null,
+        codes.length ? el("h4", {text: "Responses"}) : null,
+        el("div", {}, codes.map(function (code) {
+          var response = resolve(operation.responses[code]) || {};
+          return el("div", {}, [
+            el("p", {}, [el("strong", {text: code}), " " + (response.description || "")]),
+            el("div", {}, mediaView(response.content))
+          ]);
+        })),
+        tryIt(method, path, params, operation.requestBody)
+      ])
+    ]);
+  }
+
+  function render() {
+    var info = spec.info || {};
+    document.title = (info.title || "API") + " - Developer Console";
+    document.getElementById("info").appendChild(el("div", {}, [
+      el("h1", {}, [info.title || "API", el("span", {"class": "version", text: info.version || ""})]),
+      info.description ? el("p", {"class": "description", text: info.description}) : null,
+      el("div", {}, ["Base path: ", el("code", {text: basePath || "/"})])
+    ]));
+
+    var groups = {};
+    var paths = spec.paths || {};
+    Object.keys(paths).sort().forEach(function (path) {
+      var pathItem = resolve(paths[path]) || {};
+      methods.forEach(function (method) {
+        var operation = pathItem[method];
+        if (!operation) {
+          return;
+        }
+        var tag = (operation.tags && operation.tags[0]) || "default";
+        (groups[tag] = groups[tag] || []).push(operationView(method, path, pathItem, operation));
+      });
+    });
+
+    var descriptions = {};
+    var tags = [];
+    (spec.tags || []).forEach(function (tag) {
+      descriptions[tag.name] = tag.description;
+      if (groups[tag.name]) {
+        tags.push(tag.name);
+      }
+    });
+    Object.keys(groups).sort().forEach(function (tag) {
+      if (tags.indexOf(tag) < 0) {
+        tags.push(tag);
+      }
+    });
+    var main = document.getElementById("operations");
+    tags.forEach(function (tag) {
+      main.appendChild(el("section", {}, [
+        el("h2", {text: tag}),
+        descriptions[tag] ? el("p", {"class": "description", text: descriptions[tag]}) : null
+      ].concat(groups[tag])));
+    });
+  }
+
+  render();
+})();
+`
+
+var consolePage = template.Must(template.New("console").Parse(consoleTemplate))
+
+type consoleData struct {
+	Title    string
+	BasePath string
+	Model    json.RawMessage
+	Style    template.CSS
+	Script   template.JS
+}
+
+// ConsoleHandler serves the interactive developer console of the spec:
+// the operations grouped by the tags with the parameters, the schemas and the examples,
+// the requests are sent to the running server under the base path.
+// The console shows the same document as the spec, so the internal objects are hidden unless it is created WithInternal
+func ConsoleHandler(basePath string, spec *Spec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		serveConsole(w, r, basePath, spec)
+	}
+}
+
+func serveConsole(w http.ResponseWriter, r *http.Request, basePath string, spec *Spec) {
+	data := consoleData{
+		BasePath: basePath,
+		Model:    spec.document(),
+		Style:    template.CSS(consoleStyle),
+		Script:   template.JS(consoleScript),
+	}
+	if model := OperationFromContext(r.Context()).Model; model != nil {
+		data.Title = model.Info.Title
+	}
+	var buf bytes.Buffer
+	if err := consolePage.Execute(&buf, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Print(err)
+	}
+}
+
+// Console serves the interactive developer console of the Spec of the current operation like Model,
+// the requests are sent under the base path of the first server of the model
+func Console(w http.ResponseWriter, r *http.Request) {
+	item := OperationFromContext(r.Context())
+	basePath, err := BasePath(item.Model)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	spec, err := itemSpec(item)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	serveConsole(w, r, basePath, spec)
+}
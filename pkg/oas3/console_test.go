@@ -0,0 +1,87 @@
+package oas3
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const consoleSpec = `
+openapi: 3.0.2
+info:
+  version: "1.0.0"
+  title: "Console <Service>"
+servers:
+  - url: /api
+paths:
+  /items:
+    get:
+      operationId: items.list
+      description: "Lists the items </script><script>alert(1)</script>"
+      responses:
+        "200":
+          description: Items
+`
+
+func TestConsole(t *testing.T) {
+	t.Parallel()
+	model, err := LoadFromBytes([]byte(consoleSpec))
+	assert.NoError(t, err)
+	item := NewItem("oas3.console", model)
+	spec, err := NewSpec(model)
+	assert.NoError(t, err)
+
+	for basePath, handler := range map[string]http.HandlerFunc{
+		`"/api"`:    Console,
+		`"/v2/api"`: ConsoleHandler("/v2/api", spec),
+	} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/console", nil)
+		handler(rec, req.WithContext(WithOperation(req.Context(), item)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+		body := rec.Body.String()
+		assert.Contains(t, body, "<title>Console &lt;Service&gt; - Developer Console</title>")
+		assert.Contains(t, body, "window.OAS3_BASE_PATH = "+basePath+";")
+		assert.Contains(t, body, `"operationId":"items.list"`)
+		assert.Contains(t, body, "function tryIt(")
+		assert.Equal(t, 2, strings.Count(body, "</script>"), "the model must not close the script")
+		assert.NotContains(t, body, "src=")
+	}
+}
+
+func TestConsoleInternal(t *testing.T) {
+	t.Parallel()
+	model, err := Load("testdata/spec/internal.yaml")
+	assert.NoError(t, err)
+	item := NewItem("oas3.console", model)
+
+	get := func(handler http.HandlerFunc) string {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/console", nil)
+		handler(rec, req.WithContext(WithOperation(req.Context(), item)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+
+	public, err := NewSpec(model)
+	assert.NoError(t, err)
+	for _, handler := range []http.HandlerFunc{Console, ConsoleHandler("/", public)} {
+		body := get(handler)
+		assert.Contains(t, body, `"operationId":"items.list"`)
+		assert.NotContains(t, body, "items.purge")
+		assert.NotContains(t, body, "admin.reindex")
+	}
+
+	internal, err := NewSpec(model, WithInternal())
+	assert.NoError(t, err)
+	item.Spec = internal
+	for _, handler := range []http.HandlerFunc{Console, ConsoleHandler("/", internal)} {
+		body := get(handler)
+		assert.Contains(t, body, `"operationId":"items.purge"`)
+		assert.Contains(t, body, `"operationId":"admin.reindex"`)
+	}
+}
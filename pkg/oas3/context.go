@@ -8,8 +8,13 @@ type contextKey int
 
 const (
 	itemKey contextKey = iota
+	validationErrorsKey
 )
 
+type validationErrors struct {
+	errs []error
+}
+
 // WithOperation puts the current operation into the current context
 func WithOperation(ctx context.Context, op *Item) context.Context {
 	return context.WithValue(ctx, itemKey, op)
@@ -19,3 +24,23 @@ func WithOperation(ctx context.Context, op *Item) context.Context {
 func OperationFromContext(ctx context.Context) *Item {
 	return ctx.Value(itemKey).(*Item)
 }
+
+// WithValidationErrors puts a collector of the validation errors into the current context,
+// the Middleware reports the validation errors of the request to this collector
+func WithValidationErrors(ctx context.Context) context.Context {
+	return context.WithValue(ctx, validationErrorsKey, &validationErrors{})
+}
+
+// ValidationErrorsFromContext returns the validation errors collected for the current request
+func ValidationErrorsFromContext(ctx context.Context) []error {
+	if v, ok := ctx.Value(validationErrorsKey).(*validationErrors); ok {
+		return v.errs
+	}
+	return nil
+}
+
+func addValidationError(ctx context.Context, err error) {
+	if v, ok := ctx.Value(validationErrorsKey).(*validationErrors); ok {
+		v.errs = append(v.errs, err)
+	}
+}
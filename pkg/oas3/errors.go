@@ -0,0 +1,35 @@
+package oas3
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// Error is the structured error responded by the server,
+// Code is a stable machine-readable identifier, Message is for humans
+type Error struct {
+	Status    int    `json:"status"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Operation string `json:"operation,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// WriteError responds by the error encoded as JSON with the status of the error
+func WriteError(w http.ResponseWriter, err *Error) {
+	data, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		http.Error(w, err.Message, err.Status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(err.Status)
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		log.Print(err)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/SVilgelm/oas3-server/pkg/utils"
 
@@ -17,25 +19,44 @@ import (
 )
 
 type meta struct {
+	path                   string
 	requestSchema          *jsonschema.RootSchema
 	requestParamsNotString utils.DoubleMapBool
+	mutualTLS              bool
+	wildcard               bool
+	noCompress             bool
+	maxBodySize            int64
+	timeout                time.Duration
+	params                 []ParamInfo
 }
 
-// Item represents a connection between Route and OperationID
+// Item represents a connection between Route and OperationID,
+// Spec is the precomputed Model served by the Model and Console handlers
 type Item struct {
 	ID     string
 	Model  *openapi3.Swagger
+	Spec   *Spec
 	Routes []*mux.Route
 	meta   map[*mux.Route]meta
+	bound  bool
+}
+
+// Handle links the handler with all routes of the operation
+func (i *Item) Handle(handler http.Handler) {
+	for _, route := range i.Routes {
+		route.Handler(handler)
+	}
+	i.bound = true
+}
+
+// Bound checks if a handler is linked with the operation by Handle
+func (i *Item) Bound() bool {
+	return i.bound
 }
 
 // FindParam returns a parameter from model for given in and name
 func (i *Item) FindParam(in, name string, route *mux.Route) *openapi3.Parameter {
-	path, err := route.GetPathTemplate()
-	if err != nil {
-		log.Println("Cannot get a path template:", err.Error())
-		return nil
-	}
+	path := i.meta[route].path
 	param := i.Model.Paths[path].Parameters.GetByInAndName(in, name)
 	if param == nil {
 		methods, err := route.GetMethods()
@@ -57,6 +78,24 @@ func (i *Item) FindParam(in, name string, route *mux.Route) *openapi3.Parameter
 	return param
 }
 
+// Operation returns the operation of the model served by the route
+func (i *Item) Operation(route *mux.Route) *openapi3.Operation {
+	pathItem := i.Model.Paths[i.meta[route].path]
+	if pathItem == nil {
+		return nil
+	}
+	methods, err := route.GetMethods()
+	if err != nil {
+		return nil
+	}
+	for _, method := range methods {
+		if op := pathItem.GetOperation(method); op != nil {
+			return op
+		}
+	}
+	return nil
+}
+
 func getSchemaBuilder(params *utils.DoubleMapString, required map[string][]string) *strings.Builder {
 	size := 32 + len(*params) // `{"type":"object","properties":{}}`(33) + commas: len(params) - 1
 	for in, pr := range *params {
@@ -132,13 +171,16 @@ func getSchema(pr *openapi3.ParameterRef) (string, error) {
 	return string(schema), nil
 }
 
-// AddRoute add routes and initializes the Schemas for the operation
-func (i *Item) AddRoute(route *mux.Route, pathParameters openapi3.Parameters, operation *openapi3.Operation) error {
+// AddRoute add routes and initializes the Schemas for the operation of the model path
+func (i *Item) AddRoute(route *mux.Route, path string, operation *openapi3.Operation) error {
 	i.Routes = append(i.Routes, route)
 	params := make(utils.DoubleMapString)
 	required := make(map[string][]string)
 	routeMeta := i.meta[route]
+	routeMeta.path = path
+	pathParameters := i.Model.Paths[path].Parameters
 	routeMeta.requestParamsNotString = make(utils.DoubleMapBool)
+	routeMeta.params = nil
 
 	for _, parameters := range []openapi3.Parameters{pathParameters, operation.Parameters} {
 		for _, pr := range parameters {
@@ -147,6 +189,12 @@ func (i *Item) AddRoute(route *mux.Route, pathParameters openapi3.Parameters, op
 				return err
 			}
 			params.Set(pr.Value.In, pr.Value.Name, schema)
+			routeMeta.params = addParamInfo(routeMeta.params, ParamInfo{
+				In:       pr.Value.In,
+				Name:     pr.Value.Name,
+				Required: pr.Value.Required,
+				Type:     pr.Value.Schema.Value.Type,
+			})
 			if pr.Value.Required {
 				required[pr.Value.In] = append(required[pr.Value.In], pr.Value.Name)
 			}
@@ -158,6 +206,7 @@ func (i *Item) AddRoute(route *mux.Route, pathParameters openapi3.Parameters, op
 		return err
 	}
 	routeMeta.requestSchema = rs
+	routeMeta.mutualTLS = requiresMutualTLS(i.Model, operation)
 	i.meta[route] = routeMeta
 	return nil
 }
@@ -174,14 +223,24 @@ func NewItem(operationID string, model *openapi3.Swagger) *Item {
 
 // Mapper stores all Items
 type Mapper struct {
-	ids    map[string]*Item
-	routes map[*mux.Route]*Item
+	ids      map[string]*Item
+	routes   map[*mux.Route]*Item
+	order    []*mux.Route
+	basePath string
+}
+
+// BasePath returns the path prefix of all routes
+func (o *Mapper) BasePath() string {
+	return o.basePath
 }
 
 // Add adds new Item
 func (o *Mapper) Add(item *Item) {
 	o.ids[item.ID] = item
 	for _, route := range item.Routes {
+		if _, ok := o.routes[route]; !ok {
+			o.order = append(o.order, route)
+		}
 		o.routes[route] = item
 	}
 }
@@ -191,6 +250,27 @@ func (o *Mapper) ByID(operationID string) *Item {
 	return o.ids[operationID]
 }
 
+// IDs returns the sorted OperationIDs of all Items
+func (o *Mapper) IDs() []string {
+	ids := make([]string, 0, len(o.ids))
+	for id := range o.ids {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// MaxTimeout returns the longest timeout of the operations
+func (o *Mapper) MaxTimeout() time.Duration {
+	var res time.Duration
+	for route, item := range o.routes {
+		if timeout := item.meta[route].timeout; timeout > res {
+			res = timeout
+		}
+	}
+	return res
+}
+
 // ByRoute finds an Item by a Route
 func (o *Mapper) ByRoute(route *mux.Route) *Item {
 	return o.routes[route]
@@ -206,7 +286,12 @@ func NewMapper() *Mapper {
 }
 
 func getBoolExt(name string, extensions map[string]interface{}) (bool, error) {
-	var res bool = false
+	return getBoolExtDefault(name, extensions, false)
+}
+
+// getBoolExtDefault returns the value of the boolean extension or the default value if it is not set
+func getBoolExtDefault(name string, extensions map[string]interface{}, def bool) (bool, error) {
+	res := def
 	if raw, ok := extensions[name]; ok && raw != nil {
 		err := json.Unmarshal(raw.(json.RawMessage), &res)
 		if err != nil {
@@ -245,11 +330,20 @@ func processOperation(
 	router *mux.Router,
 	path string,
 	httpMethod string,
+	wildcards bool,
+	o *options,
 ) error {
 	pathOperation := getOperationByMethod(model.Paths[path], httpMethod)
 	if pathOperation == nil {
 		return nil
 	}
+	wildcard, err := getBoolExt("x-wildcard", pathOperation.Extensions)
+	if err != nil {
+		return err
+	}
+	if wildcard != wildcards {
+		return nil
+	}
 	if pathOperation.OperationID == "" {
 		log.Printf("No operationID for path '%s' and method '%s', skipped", path, httpMethod)
 		return nil
@@ -258,29 +352,94 @@ func processOperation(
 	if item == nil {
 		item = NewItem(pathOperation.OperationID, model)
 	}
-	wildcard, err := getBoolExt("x-wildcard", pathOperation.Extensions)
-	if err != nil {
-		return err
+	template := path
+	if o.typedPathParams {
+		template = typedPath(path, model.Paths[path], pathOperation)
 	}
 	var route *mux.Route
 	switch wildcard {
 	case true:
-		route = router.PathPrefix(path)
+		route = router.PathPrefix(template)
 	case false:
-		route = router.Path(path)
+		route = router.Path(template)
+	}
+	if err := route.GetError(); err != nil {
+		return fmt.Errorf("invalid path '%s': %v", template, err)
 	}
 	route.Methods(httpMethod).HandlerFunc(http.NotFound)
-	err = item.AddRoute(route, model.Paths[path].Parameters, pathOperation)
+	err = item.AddRoute(route, path, pathOperation)
+	if err != nil {
+		return err
+	}
+	compress, err := getBoolExtDefault("x-compress", pathOperation.Extensions, true)
 	if err != nil {
 		return err
 	}
+	bodySize, err := maxBodySize(pathOperation, o.maxBodySize)
+	if err != nil {
+		return fmt.Errorf("operation '%s': %v", pathOperation.OperationID, err)
+	}
+	timeout, err := operationTimeout(pathOperation.Extensions, o.timeout)
+	if err != nil {
+		return fmt.Errorf("operation '%s': %v", pathOperation.OperationID, err)
+	}
+	routeMeta := item.meta[route]
+	routeMeta.wildcard = wildcard
+	routeMeta.noCompress = !compress
+	routeMeta.maxBodySize = bodySize
+	routeMeta.timeout = timeout
+	item.meta[route] = routeMeta
 	mapper.Add(item)
 
 	return nil
 }
 
-// RegisterOperations creates all routes
-func RegisterOperations(model *openapi3.Swagger, router *mux.Router) (*Mapper, error) {
+type options struct {
+	basePath        *string
+	typedPathParams bool
+	maxBodySize     int64
+	timeout         time.Duration
+}
+
+// Option configures the registration of the operations
+type Option func(opts *options)
+
+// WithBasePath overrides the base path derived from the servers of the model
+func WithBasePath(path string) Option {
+	return func(opts *options) {
+		opts.basePath = &path
+	}
+}
+
+// WithTypedPathParams matches the path parameters by their schemas: integer, number, boolean, uuid, enum and pattern,
+// so the paths with wrong parameters fall through to other routes or 404 instead of 400
+func WithTypedPathParams() Option {
+	return func(opts *options) {
+		opts.typedPathParams = true
+	}
+}
+
+// WithMaxBodySize limits the request bodies of the operations without the x-max-body-size extension,
+// the bodies over the limit are rejected by the Middleware with 413
+func WithMaxBodySize(size int64) Option {
+	return func(opts *options) {
+		opts.maxBodySize = size
+	}
+}
+
+// WithTimeout sets the timeout of the operations without the x-timeout extension,
+// the Middleware runs the handlers with the deadline in the context of the request
+func WithTimeout(timeout time.Duration) Option {
+	return func(opts *options) {
+		opts.timeout = timeout
+	}
+}
+
+// RegisterOperations creates all routes,
+// the routes are mounted under the base path of the first server of the model.
+// The static segments are registered before the templated ones, the longer paths before the shorter ones
+// and the x-wildcard prefixes after all paths, so the most specific route matches a request.
+func RegisterOperations(model *openapi3.Swagger, router *mux.Router, opts ...Option) (*Mapper, error) {
 	allMethods := []string{
 		http.MethodGet,
 		http.MethodHead,
@@ -293,19 +452,48 @@ func RegisterOperations(model *openapi3.Swagger, router *mux.Router) (*Mapper, e
 		http.MethodTrace,
 	}
 
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	mapper := NewMapper()
 	if model == nil {
 		return mapper, nil
 	}
-	for path, meta := range model.Paths {
-		if meta == nil {
-			log.Printf("Wrong path '%s' definition, skipped", path)
-			continue
+	if o.basePath != nil {
+		mapper.basePath = NormalizeBasePath(*o.basePath)
+	} else {
+		basePath, err := BasePath(model)
+		if err != nil {
+			return nil, err
 		}
-		for _, httpMethod := range allMethods {
-			err := processOperation(mapper, model, router, path, httpMethod)
-			if err != nil {
-				return nil, err
+		mapper.basePath = basePath
+	}
+	if mapper.basePath != "" {
+		router = router.PathPrefix(mapper.basePath).Subrouter()
+	}
+	warnings, err := CheckPaths(model)
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range warnings {
+		log.Println("Ambiguous paths:", warning)
+	}
+	paths := sortedPaths(model.Paths)
+	for _, wildcards := range []bool{false, true} {
+		for _, path := range paths {
+			if model.Paths[path] == nil {
+				if !wildcards {
+					log.Printf("Wrong path '%s' definition, skipped", path)
+				}
+				continue
+			}
+			for _, httpMethod := range allMethods {
+				err := processOperation(mapper, model, router, path, httpMethod, wildcards, &o)
+				if err != nil {
+					return nil, err
+				}
 			}
 		}
 	}
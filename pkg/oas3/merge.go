@@ -0,0 +1,177 @@
+package oas3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// cleanExtensions returns only the extensions and the top-level tags,
+// the loader keeps all raw fields of the document in the extensions,
+// they must not override the modified fields during marshaling
+func cleanExtensions(extensions map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{})
+	for k, v := range extensions {
+		if strings.HasPrefix(k, "x-") || k == "tags" {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// Tags returns the top-level tags of the model
+func Tags(model *openapi3.Swagger) (openapi3.Tags, error) {
+	raw, ok := model.Extensions["tags"].(json.RawMessage)
+	if !ok {
+		return nil, nil
+	}
+	var tags openapi3.Tags
+	if err := json.Unmarshal(raw, &tags); err != nil {
+		return nil, fmt.Errorf("invalid tags: %v", err)
+	}
+	return tags, nil
+}
+
+func sameJSON(a, b interface{}) bool {
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aData, bData)
+}
+
+type merger struct {
+	model     *openapi3.Swagger
+	paths     map[string]int
+	ids       map[string]int
+	tags      map[string]int
+	tagsList  openapi3.Tags
+	conflicts []string
+}
+
+func (m *merger) conflict(format string, args ...interface{}) {
+	m.conflicts = append(m.conflicts, fmt.Sprintf(format, args...))
+}
+
+func (m *merger) mergePaths(src *openapi3.Swagger, n int) {
+	for path, item := range src.Paths {
+		if prev, ok := m.paths[path]; ok {
+			m.conflict("path '%s' is defined in #%d and #%d", path, prev, n)
+			continue
+		}
+		m.paths[path] = n
+		m.model.Paths[path] = item
+		if item == nil {
+			continue
+		}
+		for _, op := range item.Operations() {
+			if op.OperationID == "" {
+				continue
+			}
+			if prev, ok := m.ids[op.OperationID]; ok {
+				m.conflict("operationId '%s' is defined in #%d and #%d", op.OperationID, prev, n)
+				continue
+			}
+			m.ids[op.OperationID] = n
+		}
+	}
+}
+
+// mergeComponents merges every map of the components, the same definitions of a component are allowed
+func (m *merger) mergeComponents(src *openapi3.Swagger, n int, owners map[string]int) {
+	dst := reflect.ValueOf(&m.model.Components).Elem()
+	from := reflect.ValueOf(&src.Components).Elem()
+	for i := 0; i < dst.NumField(); i++ {
+		field := dst.Field(i)
+		if field.Kind() != reflect.Map {
+			continue
+		}
+		name := strings.Split(dst.Type().Field(i).Tag.Get("json"), ",")[0]
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(field.Type()))
+		}
+		iter := from.Field(i).MapRange()
+		for iter.Next() {
+			key := name + "/" + iter.Key().String()
+			if existing := field.MapIndex(iter.Key()); existing.IsValid() {
+				if !sameJSON(existing.Interface(), iter.Value().Interface()) {
+					m.conflict("component '%s' is defined differently in #%d and #%d", key, owners[key], n)
+				}
+				continue
+			}
+			owners[key] = n
+			field.SetMapIndex(iter.Key(), iter.Value())
+		}
+	}
+}
+
+func (m *merger) mergeTags(src *openapi3.Swagger, n int) {
+	tags, err := Tags(src)
+	if err != nil {
+		m.conflict("#%d: %v", n, err)
+		return
+	}
+	for _, tag := range tags {
+		if prev, ok := m.tags[tag.Name]; ok {
+			if !sameJSON(tag, m.tagsList.Get(tag.Name)) {
+				m.conflict("tag '%s' is defined differently in #%d and #%d", tag.Name, prev, n)
+			}
+			continue
+		}
+		m.tags[tag.Name] = n
+		m.tagsList = append(m.tagsList, tag)
+	}
+}
+
+// Merge combines several specifications into one.
+// The paths, the components, the tags and the security schemes are merged,
+// the duplicated paths and operationIds and the different components with the same name are reported as conflicts.
+// The openapi version, info, servers and security of the first specification are used.
+func Merge(models ...*openapi3.Swagger) (*openapi3.Swagger, error) {
+	if len(models) == 0 {
+		return nil, fmt.Errorf("nothing to merge")
+	}
+	first := models[0]
+	m := merger{
+		model: &openapi3.Swagger{
+			ExtensionProps: openapi3.ExtensionProps{Extensions: cleanExtensions(first.Extensions)},
+			OpenAPI:        first.OpenAPI,
+			Info:           first.Info,
+			Servers:        first.Servers,
+			Paths:          make(openapi3.Paths),
+			Components:     openapi3.NewComponents(),
+			Security:       first.Security,
+			ExternalDocs:   first.ExternalDocs,
+		},
+		paths: make(map[string]int),
+		ids:   make(map[string]int),
+		tags:  make(map[string]int),
+	}
+	owners := make(map[string]int)
+	for i, model := range models {
+		n := i + 1
+		m.mergePaths(model, n)
+		m.mergeComponents(model, n, owners)
+		m.mergeTags(model, n)
+	}
+	if len(m.conflicts) > 0 {
+		sort.Strings(m.conflicts)
+		return nil, fmt.Errorf("conflicting specifications: %s", strings.Join(m.conflicts, "; "))
+	}
+	delete(m.model.Extensions, "tags")
+	if len(m.tagsList) > 0 {
+		data, err := json.Marshal(m.tagsList)
+		if err != nil {
+			return nil, err
+		}
+		m.model.Extensions["tags"] = json.RawMessage(data)
+	}
+	if err := validateModel(context.Background(), m.model); err != nil {
+		return nil, err
+	}
+	return m.model, nil
+}
@@ -0,0 +1,51 @@
+package oas3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+	users, err := Load("testdata/merge/users.yaml")
+	assert.NoError(t, err)
+	orders, err := Load("testdata/merge/orders.yaml")
+	assert.NoError(t, err)
+	conflict, err := Load("testdata/merge/conflict.yaml")
+	assert.NoError(t, err)
+
+	_, err = Merge()
+	assert.Error(t, err)
+
+	model, err := Merge(users, orders)
+	assert.NoError(t, err)
+	assert.Equal(t, "Service", model.Info.Title)
+	assert.Equal(t, "http://localhost/api", model.Servers[0].URL)
+	assert.NotNil(t, model.Paths["/users"])
+	assert.NotNil(t, model.Paths["/orders"])
+	assert.NotNil(t, model.Components.Schemas["Error"])
+	assert.NotNil(t, model.Components.Responses["Error"])
+	assert.NotNil(t, model.Components.SecuritySchemes["token"])
+	assert.NotNil(t, model.Components.SecuritySchemes["apiKey"])
+	tags, err := Tags(model)
+	assert.NoError(t, err)
+	assert.Len(t, tags, 2)
+	assert.Equal(t, "users", tags[0].Name)
+	assert.Equal(t, "orders", tags[1].Name)
+
+	data, err := json.Marshal(model)
+	assert.NoError(t, err)
+	var raw map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &raw))
+	assert.Len(t, raw["paths"], 2)
+	assert.Len(t, raw["tags"], 2)
+
+	_, err = Merge(users, orders, conflict)
+	assert.EqualError(t, err, "conflicting specifications: "+
+		"component 'schemas/Error' is defined differently in #1 and #3; "+
+		"operationId 'orders.list' is defined in #2 and #3; "+
+		"path '/users' is defined in #1 and #3; "+
+		"tag 'users' is defined differently in #1 and #3")
+}
@@ -203,8 +203,17 @@ func (m *MiddlewareHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	ctx := WithOperation(r.Context(), item)
 	r = r.WithContext(ctx)
+	if item.meta[route].mutualTLS && ClientCertificate(r) == nil {
+		http.Error(w, "a verified client certificate is required", http.StatusUnauthorized)
+		return
+	}
+	body, ok := limitBody(w, r, item.ID, item.meta[route].maxBodySize)
+	if !ok {
+		return
+	}
 	if m.doRequestValidation {
 		if err := validateRequest(r, item, route); err != nil {
+			addValidationError(ctx, err)
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
@@ -214,11 +223,25 @@ func (m *MiddlewareHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			ResponseWriter: w,
 			buf:            new(bytes.Buffer),
 		}
-		m.next.ServeHTTP(&rw, r)
+		m.serve(&rw, r, item, route, body)
 		rw.send()
 	} else {
-		m.next.ServeHTTP(w, r)
+		m.serve(w, r, item, route, body)
+	}
+}
+
+// serve calls the handler with the deadline of the operation if it has the timeout,
+// the handler reading the limited body over the limit without responding is answered by 413
+func (m *MiddlewareHandler) serve(w http.ResponseWriter, r *http.Request, item *Item, route *mux.Route, body *limitedBody) {
+	next := m.next
+	if body != nil {
+		next = respondTooLarge(next, body)
+	}
+	if timeout := item.meta[route].timeout; timeout > 0 {
+		serveWithTimeout(w, r, next, item.ID, timeout)
+		return
 	}
+	next.ServeHTTP(w, r)
 }
 
 // Middleware puts the model into the current context and run validations
@@ -0,0 +1,177 @@
+package oas3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// maxMockDepth limits the nesting of the generated values, the recursive schemas are cut at this depth
+const maxMockDepth = 8
+
+var mockFormats = map[string]string{
+	"date":      "1970-01-01",
+	"date-time": "1970-01-01T00:00:00Z",
+	"email":     "user@example.com",
+	"hostname":  "example.com",
+	"ipv4":      "127.0.0.1",
+	"ipv6":      "::1",
+	"uri":       "http://example.com",
+	"url":       "http://example.com",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+}
+
+// MockValue generates a value valid for the schema: the example, the default, the first enum value
+// or the minimal value of the type
+func MockValue(schema *openapi3.Schema) interface{} {
+	return mockValue(schema, 0)
+}
+
+func mockValue(schema *openapi3.Schema, depth int) interface{} {
+	if schema == nil || depth > maxMockDepth {
+		return nil
+	}
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	}
+	for _, refs := range [][]*openapi3.SchemaRef{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		if len(refs) > 0 && refs[0] != nil && schema.Type == "" && len(schema.Properties) == 0 {
+			return mockValue(refs[0].Value, depth+1)
+		}
+	}
+	switch schema.Type {
+	case "string":
+		if value, ok := mockFormats[schema.Format]; ok {
+			return value
+		}
+		value := "string"
+		for uint64(len(value)) < schema.MinLength {
+			value += value
+		}
+		if schema.MaxLength != nil && uint64(len(value)) > *schema.MaxLength {
+			value = value[:*schema.MaxLength]
+		}
+		return value
+	case "integer", "number":
+		if schema.Min != nil {
+			return *schema.Min
+		}
+		if schema.Max != nil && *schema.Max < 0 {
+			return *schema.Max
+		}
+		return 0
+	case "boolean":
+		return false
+	case "array":
+		items := []interface{}{}
+		if schema.Items == nil {
+			return items
+		}
+		for i := uint64(0); i < 1 || i < schema.MinItems; i++ {
+			if item := mockValue(schema.Items.Value, depth+1); item != nil {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	obj := make(map[string]interface{}, len(schema.Properties))
+	for name, prop := range schema.Properties {
+		if prop == nil || prop.Value == nil || prop.Value.WriteOnly {
+			continue
+		}
+		if value := mockValue(prop.Value, depth+1); value != nil {
+			obj[name] = value
+		}
+	}
+	return obj
+}
+
+// mockResponse finds the successful response with the lowest status code or the default one
+func mockResponse(operation *openapi3.Operation) (int, *openapi3.Response) {
+	codes := make([]int, 0, len(operation.Responses))
+	for code := range operation.Responses {
+		if status, err := strconv.Atoi(code); err == nil && status >= 200 && status < 300 {
+			codes = append(codes, status)
+		}
+	}
+	if len(codes) > 0 {
+		sort.Ints(codes)
+		if ref := operation.Responses[strconv.Itoa(codes[0])]; ref != nil {
+			return codes[0], ref.Value
+		}
+		return codes[0], nil
+	}
+	if ref := operation.Responses.Default(); ref != nil {
+		return http.StatusOK, ref.Value
+	}
+	return http.StatusOK, nil
+}
+
+// mockContent returns the example of the media type or the value generated from its schema
+func mockContent(mediaType *openapi3.MediaType) interface{} {
+	if mediaType == nil {
+		return nil
+	}
+	if mediaType.Example != nil {
+		return mediaType.Example
+	}
+	names := make([]string, 0, len(mediaType.Examples))
+	for name := range mediaType.Examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if example := mediaType.Examples[name]; example != nil && example.Value != nil && example.Value.Value != nil {
+			return example.Value.Value
+		}
+	}
+	if mediaType.Schema == nil {
+		return nil
+	}
+	return MockValue(mediaType.Schema.Value)
+}
+
+// MockHandler responds by the successful response of the operation,
+// the body is the example of the response or the value generated from its schema
+func MockHandler(operation *openapi3.Operation) http.HandlerFunc {
+	status, response := mockResponse(operation)
+	var contentType string
+	var body []byte
+	if response != nil && len(response.Content) > 0 {
+		contentTypes := make([]string, 0, len(response.Content))
+		for ct := range response.Content {
+			contentTypes = append(contentTypes, ct)
+		}
+		sort.Strings(contentTypes)
+		contentType = contentTypes[0]
+		if _, ok := response.Content["application/json"]; ok {
+			contentType = "application/json"
+		}
+		value := mockContent(response.Content[contentType])
+		if text, ok := value.(string); ok && contentType != "application/json" {
+			body = []byte(text)
+		} else if data, err := json.Marshal(value); err == nil {
+			body = data
+		} else {
+			body = []byte(fmt.Sprint(value))
+		}
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(status)
+		if r.Method != http.MethodHead {
+			_, _ = w.Write(body)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package oas3
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockValue(t *testing.T) {
+	t.Parallel()
+	maxLength := uint64(3)
+	min := 5.0
+	assert.Nil(t, MockValue(nil))
+	assert.Equal(t, "string", MockValue(&openapi3.Schema{Type: "string"}))
+	assert.Equal(t, "str", MockValue(&openapi3.Schema{Type: "string", MaxLength: &maxLength}))
+	assert.Equal(t, "stringstring", MockValue(&openapi3.Schema{Type: "string", MinLength: 7}))
+	assert.Equal(t, "1970-01-01T00:00:00Z", MockValue(&openapi3.Schema{Type: "string", Format: "date-time"}))
+	assert.Equal(t, "b", MockValue(&openapi3.Schema{Type: "string", Enum: []interface{}{"b", "a"}}))
+	assert.Equal(t, 5.0, MockValue(&openapi3.Schema{Type: "integer", Min: &min}))
+	assert.Equal(t, false, MockValue(&openapi3.Schema{Type: "boolean"}))
+	assert.Equal(t, []interface{}{0, 0}, MockValue(&openapi3.Schema{
+		Type:     "array",
+		MinItems: 2,
+		Items:    &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "number"}},
+	}))
+	assert.Equal(t, map[string]interface{}{"name": "Rex"}, MockValue(&openapi3.Schema{
+		Type: "object",
+		Properties: map[string]*openapi3.SchemaRef{
+			"name":     {Value: &openapi3.Schema{Type: "string", Example: "Rex"}},
+			"password": {Value: &openapi3.Schema{Type: "string", WriteOnly: true}},
+		},
+	}))
+
+	recursive := &openapi3.Schema{Type: "object", Properties: map[string]*openapi3.SchemaRef{}}
+	recursive.Properties["child"] = &openapi3.SchemaRef{Value: recursive}
+	assert.NotNil(t, MockValue(recursive))
+}
+
+func TestMockHandler(t *testing.T) {
+	t.Parallel()
+	model, err := Load("testdata/routes/typed.yaml")
+	assert.NoError(t, err)
+	rec := httptest.NewRecorder()
+	MockHandler(model.Paths["/items/{id}"].Get).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	operation := &openapi3.Operation{Responses: openapi3.Responses{
+		"default": {Value: &openapi3.Response{Content: openapi3.Content{
+			"text/plain": {Example: "hello"},
+		}}},
+	}}
+	rec = httptest.NewRecorder()
+	MockHandler(operation).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "hello", rec.Body.String())
+}
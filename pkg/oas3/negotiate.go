@@ -0,0 +1,56 @@
+package oas3
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// accepted is a value of the Accept or Accept-Encoding header with its quality
+type accepted struct {
+	value   string
+	quality float64
+}
+
+// parseAccept parses the values of the Accept-like header ordered by the quality,
+// the values with the same quality keep the order of the header, the values with q=0 are rejected ones
+func parseAccept(header string) []accepted {
+	var res []accepted
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		value := strings.ToLower(strings.TrimSpace(params[0]))
+		if value == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			q, err := strconv.ParseFloat(param[2:], 64)
+			if err == nil {
+				quality = q
+			}
+		}
+		res = append(res, accepted{value: value, quality: quality})
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].quality > res[j].quality
+	})
+	return res
+}
+
+// acceptsEncoding checks if the Accept-Encoding header allows the encoding
+func acceptsEncoding(header, encoding string) bool {
+	wildcard := false
+	for _, a := range parseAccept(header) {
+		switch a.value {
+		case encoding:
+			return a.quality > 0
+		case "*":
+			wildcard = a.quality > 0
+		}
+	}
+	return wildcard
+}
@@ -2,77 +2,114 @@ package oas3
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"net/http"
-
-	"github.com/SVilgelm/oas3-server/pkg/utils"
+	"net/url"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
-	"github.com/ghodss/yaml"
 )
 
-// Load parses the YAML/JSON-encoded file with OpenApi 3 Specification
-func Load(fileName string) (*openapi3.Swagger, error) {
-	model, err := openapi3.NewSwaggerLoader().LoadSwaggerFromFile(fileName)
+// LoadOption configures the loader of the specification
+type LoadOption func(loader *openapi3.SwaggerLoader)
+
+// WithExternalRefs allows the references to the components of other files,
+// the relative references are resolved against the location of the referring file
+func WithExternalRefs() LoadOption {
+	return func(loader *openapi3.SwaggerLoader) {
+		loader.IsExternalRefsAllowed = true
+	}
+}
+
+func newLoader(opts []LoadOption) *openapi3.SwaggerLoader {
+	loader := openapi3.NewSwaggerLoader()
+	for _, opt := range opts {
+		opt(loader)
+	}
+	return loader
+}
+
+func validate(model *openapi3.Swagger, err error) (*openapi3.Swagger, error) {
 	if err != nil {
 		return nil, err
 	}
-	err = model.Validate(context.Background())
+	err = validateModel(context.Background(), model)
 	if err != nil {
 		return nil, err
 	}
-	log.Println("Loaded OpenAPI 3 Specification file:", fileName)
 	return model, nil
 }
 
-// Model returns the OAS3 model in JSON/YAML format
-func Model(w http.ResponseWriter, r *http.Request) {
-	item := OperationFromContext(r.Context())
-	types := utils.GetContentTypes(r)
-	var data []byte
-	var err error
-	var contentType string
-	if utils.Contains(types, "application/json") {
-		contentType = "application/json"
-		data, err = json.Marshal(item.Model)
-	} else if utils.Contains(types, "application/yaml") {
-		contentType = "application/yaml"
-		data, err = yaml.Marshal(item.Model)
-	} else {
-		http.Error(
-			w,
-			"Supported media type not found. Use 'application/json' or 'application/yaml'",
-			http.StatusUnsupportedMediaType,
-		)
-		return
-	}
+// Load parses the YAML/JSON-encoded file with OpenApi 3 Specification
+func Load(fileName string, opts ...LoadOption) (*openapi3.Swagger, error) {
+	model, err := validate(newLoader(opts).LoadSwaggerFromFile(fileName))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return nil, err
 	}
+	log.Println("Loaded OpenAPI 3 Specification file:", fileName)
+	return model, nil
+}
 
-	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
-	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write(data); err != nil {
-		log.Print(err)
-		return
+// LoadFromBytes parses the YAML/JSON-encoded OpenApi 3 Specification,
+// the relative external references are resolved against the current directory
+func LoadFromBytes(data []byte, opts ...LoadOption) (*openapi3.Swagger, error) {
+	return validate(newLoader(opts).LoadSwaggerFromData(data))
+}
+
+// LoadFromReader parses the YAML/JSON-encoded OpenApi 3 Specification read from r
+func LoadFromReader(r io.Reader, opts ...LoadOption) (*openapi3.Swagger, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
 	}
+	return LoadFromBytes(data, opts...)
 }
 
-// Console returns the OAS3 Developer console
-func Console(w http.ResponseWriter, r *http.Request) {
-	item := OperationFromContext(r.Context())
+// LoadFromFS parses the YAML/JSON-encoded file of the file system with OpenApi 3 Specification,
+// the external references are read from the same file system
+func LoadFromFS(fs http.FileSystem, fileName string, opts ...LoadOption) (*openapi3.Swagger, error) {
+	loader := newLoader(opts)
+	loader.LoadSwaggerFromURIFunc = func(loader *openapi3.SwaggerLoader, location *url.URL) (*openapi3.Swagger, error) {
+		if location.Scheme != "" || location.Host != "" {
+			return nil, fmt.Errorf("unsupported URI: '%s'", location.String())
+		}
+		f, err := fs.Open(location.Path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		data, err := ioutil.ReadAll(f)
+		if err != nil {
+			return nil, err
+		}
+		return loader.LoadSwaggerFromDataWithPath(data, location)
+	}
+	model, err := validate(loader.LoadSwaggerFromFile(fileName))
+	if err != nil {
+		return nil, err
+	}
+	log.Println("Loaded OpenAPI 3 Specification file:", fileName)
+	return model, nil
+}
 
-	data, err := yaml.Marshal(item.Model)
+// LoadFromURL parses the YAML/JSON-encoded OpenApi 3 Specification downloaded from the URL
+func LoadFromURL(rawURL string, opts ...LoadOption) (*openapi3.Swagger, error) {
+	location, err := url.Parse(rawURL)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return nil, err
 	}
-	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write(data); err != nil {
-		log.Print(err)
-		return
+	model, err := validate(newLoader(opts).LoadSwaggerFromURI(location))
+	if err != nil {
+		return nil, err
 	}
+	log.Println("Loaded OpenAPI 3 Specification URL:", rawURL)
+	return model, nil
+}
+
+// IsURL checks that the location of the specification is an URL
+func IsURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
 }
@@ -0,0 +1,62 @@
+package oas3
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertItemsModel(t *testing.T, model *openapi3.Swagger) {
+	t.Helper()
+	assert.NotNil(t, model)
+	op := model.Paths["/items/{id}"].Get
+	assert.Equal(t, "items.get", op.OperationID)
+	assert.Equal(t, "integer", op.Parameters[0].Value.Schema.Value.Type)
+	schema := op.Responses["200"].Value.Content["application/json"].Schema.Value
+	assert.Equal(t, "string", schema.Properties["name"].Value.Type)
+}
+
+func TestLoadExternalRefs(t *testing.T) {
+	t.Parallel()
+	_, err := Load("testdata/api/main.yaml")
+	assert.Error(t, err)
+
+	model, err := Load("testdata/api/main.yaml", WithExternalRefs())
+	assert.NoError(t, err)
+	assertItemsModel(t, model)
+
+	model, err = LoadFromFS(http.Dir("testdata"), "api/main.yaml", WithExternalRefs())
+	assert.NoError(t, err)
+	assertItemsModel(t, model)
+
+	_, err = LoadFromFS(http.Dir("testdata"), "api/missing.yaml", WithExternalRefs())
+	assert.Error(t, err)
+
+	srv := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer srv.Close()
+	assert.True(t, IsURL(srv.URL))
+	model, err = LoadFromURL(srv.URL+"/api/main.yaml", WithExternalRefs())
+	assert.NoError(t, err)
+	assertItemsModel(t, model)
+}
+
+func TestLoadFromBytes(t *testing.T) {
+	t.Parallel()
+	data, err := ioutil.ReadFile("testdata/api/components.yaml")
+	assert.NoError(t, err)
+	model, err := LoadFromBytes(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "Components", model.Info.Title)
+
+	model, err = LoadFromReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, "Components", model.Info.Title)
+
+	_, err = LoadFromBytes([]byte("not a specification"))
+	assert.Error(t, err)
+}
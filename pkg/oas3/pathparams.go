@@ -0,0 +1,100 @@
+package oas3
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	integerRe = `-?[0-9]+`
+	numberRe  = `-?[0-9]+(?:\.[0-9]+)?(?:[eE][-+]?[0-9]+)?`
+	booleanRe = `true|false`
+	uuidRe    = `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`
+)
+
+// pathParam finds the path parameter of the operation, the operation parameters override the path ones
+func pathParam(pathItem *openapi3.PathItem, operation *openapi3.Operation, name string) *openapi3.Parameter {
+	if param := operation.Parameters.GetByInAndName(openapi3.ParameterInPath, name); param != nil {
+		return param
+	}
+	return pathItem.Parameters.GetByInAndName(openapi3.ParameterInPath, name)
+}
+
+// anchoredPattern returns the pattern without the anchors if it must match the whole value, e.g. '^[0-9]+$'.
+// The schema patterns match a part of the value unless they are anchored at both ends,
+// so the unanchored ones and the ones like '^a|b$' can't be used for a path segment.
+// The expressions with capturing groups can't be used by gorilla mux, it accepts only non-capturing groups.
+func anchoredPattern(pattern string) (string, bool) {
+	if !strings.HasPrefix(pattern, "^") || !strings.HasSuffix(pattern, "$") {
+		return "", false
+	}
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil || re.MaxCap() > 0 || re.Op != syntax.OpConcat || len(re.Sub) < 2 {
+		return "", false
+	}
+	if re.Sub[0].Op != syntax.OpBeginText || re.Sub[len(re.Sub)-1].Op != syntax.OpEndText {
+		return "", false
+	}
+	return pattern[1 : len(pattern)-1], true
+}
+
+// schemaRegexp derives the regular expression of a path segment from the schema,
+// the empty string means any value
+func schemaRegexp(schema *openapi3.Schema) string {
+	if schema == nil {
+		return ""
+	}
+	if len(schema.Enum) > 0 {
+		values := make([]string, 0, len(schema.Enum))
+		for _, value := range schema.Enum {
+			switch v := value.(type) {
+			case string:
+				values = append(values, regexp.QuoteMeta(v))
+			case float64:
+				values = append(values, regexp.QuoteMeta(strconv.FormatFloat(v, 'f', -1, 64)))
+			case bool:
+				values = append(values, strconv.FormatBool(v))
+			default:
+				return ""
+			}
+		}
+		return strings.Join(values, "|")
+	}
+	switch schema.Type {
+	case "integer":
+		return integerRe
+	case "number":
+		return numberRe
+	case "boolean":
+		return booleanRe
+	case "string":
+		if schema.Format == "uuid" {
+			return uuidRe
+		}
+		if expr, ok := anchoredPattern(schema.Pattern); ok {
+			return expr
+		}
+	}
+	return ""
+}
+
+// typedPath adds the regular expressions derived from the schemas to the parameters of the path template,
+// e.g. '/items/{id}' with the integer id becomes '/items/{id:-?[0-9]+}'
+func typedPath(path string, pathItem *openapi3.PathItem, operation *openapi3.Operation) string {
+	return pathParamRe.ReplaceAllStringFunc(path, func(param string) string {
+		name := param[1 : len(param)-1]
+		p := pathParam(pathItem, operation, name)
+		if p == nil || p.Schema == nil {
+			return param
+		}
+		expr := schemaRegexp(p.Schema.Value)
+		if expr == "" {
+			return param
+		}
+		return "{" + name + ":" + expr + "}"
+	})
+}
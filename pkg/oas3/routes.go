@@ -0,0 +1,326 @@
+package oas3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var pathParamRe = regexp.MustCompile(`\{[^}]*\}`)
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func isTemplated(segment string) bool {
+	return strings.Contains(segment, "{")
+}
+
+// normalizePath replaces the names of the path parameters by the empty ones,
+// the paths with the same normalized form match the same requests
+func normalizePath(path string) string {
+	return pathParamRe.ReplaceAllString(path, "{}")
+}
+
+// lessPath orders the paths so that more specific paths are registered first:
+// static segments before templated ones and longer paths before their prefixes
+func lessPath(a, b string) bool {
+	sa, sb := splitPath(a), splitPath(b)
+	for i := 0; i < len(sa) && i < len(sb); i++ {
+		ta, tb := isTemplated(sa[i]), isTemplated(sb[i])
+		if ta != tb {
+			return !ta
+		}
+		if sa[i] != sb[i] {
+			if len(sa[i]) != len(sb[i]) {
+				return len(sa[i]) > len(sb[i])
+			}
+			return sa[i] < sb[i]
+		}
+	}
+	if len(sa) != len(sb) {
+		return len(sa) > len(sb)
+	}
+	return a < b
+}
+
+// sortedPaths returns the paths of the model in the registration order
+func sortedPaths(paths openapi3.Paths) []string {
+	res := make([]string, 0, len(paths))
+	for path := range paths {
+		res = append(res, path)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return lessPath(res[i], res[j])
+	})
+	return res
+}
+
+// ambiguousPaths checks if a request can match both paths and none of them is more specific,
+// e.g. '/users/{id}/profile' and '/{type}/me/profile'
+func ambiguousPaths(a, b string) bool {
+	sa, sb := splitPath(a), splitPath(b)
+	if len(sa) != len(sb) {
+		return false
+	}
+	var aFirst, bFirst bool
+	for i := range sa {
+		ta, tb := isTemplated(sa[i]), isTemplated(sb[i])
+		switch {
+		case !ta && !tb:
+			if sa[i] != sb[i] {
+				return false
+			}
+		case !ta:
+			aFirst = true
+		case !tb:
+			bFirst = true
+		}
+	}
+	return aFirst && bFirst
+}
+
+func sharedMethods(a, b *openapi3.PathItem) []string {
+	var methods []string
+	operations := b.Operations()
+	for method := range a.Operations() {
+		if operations[method] != nil {
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// CheckPaths reports the paths of the model that conflict or shadow each other.
+// The paths that differ only by the names of the parameters and share a method are the conflicts,
+// one of the operations is unreachable, so they are returned as the error.
+// The ambiguous paths are matched in the registration order and are returned as the warnings.
+func CheckPaths(model *openapi3.Swagger) ([]string, error) {
+	if model == nil {
+		return nil, nil
+	}
+	paths := sortedPaths(model.Paths)
+	var warnings, conflicts []string
+	for i, a := range paths {
+		for _, b := range paths[i+1:] {
+			if model.Paths[a] == nil || model.Paths[b] == nil {
+				continue
+			}
+			methods := sharedMethods(model.Paths[a], model.Paths[b])
+			if len(methods) == 0 {
+				continue
+			}
+			if normalizePath(a) == normalizePath(b) {
+				conflicts = append(conflicts, fmt.Sprintf(
+					"paths '%s' and '%s' conflict for %s", a, b, strings.Join(methods, ", "),
+				))
+			} else if ambiguousPaths(a, b) {
+				warnings = append(warnings, fmt.Sprintf(
+					"path '%s' shadows '%s' for %s", a, b, strings.Join(methods, ", "),
+				))
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return warnings, fmt.Errorf("conflicting paths: %s", strings.Join(conflicts, "; "))
+	}
+	return warnings, nil
+}
+
+// coversSegment checks if the segment of a route matches all values of the segment of another route,
+// the parameters with the patterns match only some of them
+func coversSegment(a, b string) bool {
+	if a == b {
+		return true
+	}
+	return isTemplated(a) && !strings.Contains(a, ":")
+}
+
+// coversRoute checks if the route a matches all requests of the route b
+func coversRoute(a, b RouteInfo) bool {
+	if a.Method != b.Method || b.Wildcard && !a.Wildcard {
+		return false
+	}
+	sa, sb := splitPath(a.Path), splitPath(b.Path)
+	if a.Wildcard && strings.Trim(a.Path, "/") == "" {
+		// the root prefix matches all paths
+		sa = nil
+	}
+	if len(sa) > len(sb) || !a.Wildcard && len(sa) != len(sb) {
+		return false
+	}
+	for i := range sa {
+		if !coversSegment(sa[i], sb[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func routeName(route RouteInfo) string {
+	name := fmt.Sprintf("%s %s (%s)", route.Method, route.Path, route.OperationID)
+	if route.API != "" {
+		name += " of the API '" + route.API + "'"
+	}
+	return name
+}
+
+// CheckRoutes reports the routes that never match because a route registered before them
+// matches all their requests, the routes must be in the matching order like the ones of Mapper.Walk.
+// It is used for the routes of several mappers sharing a router, the paths of one model are checked by CheckPaths.
+func CheckRoutes(routes []RouteInfo) error {
+	var shadowed []string
+	for i, b := range routes {
+		for _, a := range routes[:i] {
+			if coversRoute(a, b) {
+				shadowed = append(shadowed, fmt.Sprintf("%s shadows %s", routeName(a), routeName(b)))
+				break
+			}
+		}
+	}
+	if len(shadowed) > 0 {
+		return fmt.Errorf("unreachable routes: %s", strings.Join(shadowed, "; "))
+	}
+	return nil
+}
+
+// ParamInfo describes a parameter of a route
+type ParamInfo struct {
+	In       string `json:"in"`
+	Name     string `json:"name"`
+	Required bool   `json:"required,omitempty"`
+	Type     string `json:"type,omitempty"`
+}
+
+// addParamInfo adds the parameter, the operation parameters override the path ones
+func addParamInfo(params []ParamInfo, param ParamInfo) []ParamInfo {
+	for i, p := range params {
+		if p.In == param.In && p.Name == param.Name {
+			params[i] = param
+			return params
+		}
+	}
+	return append(params, param)
+}
+
+// RouteInfo describes a registered route
+type RouteInfo struct {
+	API         string      `json:"api,omitempty"`
+	OperationID string      `json:"operation_id"`
+	Method      string      `json:"method"`
+	Path        string      `json:"path"`
+	Wildcard    bool        `json:"wildcard,omitempty"`
+	Bound       bool        `json:"bound"`
+	Params      []ParamInfo `json:"params,omitempty"`
+}
+
+// Walk calls the function for every route in the registration order, which is the matching order,
+// the walking stops on the first error
+func (o *Mapper) Walk(fn func(route RouteInfo) error) error {
+	for _, route := range o.order {
+		item := o.routes[route]
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+		routeMeta := item.meta[route]
+		for _, method := range methods {
+			err := fn(RouteInfo{
+				OperationID: item.ID,
+				Method:      method,
+				Path:        path,
+				Wildcard:    routeMeta.wildcard,
+				Bound:       item.bound,
+				Params:      routeMeta.params,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Routes returns the routes in the registration order
+func (o *Mapper) Routes() []RouteInfo {
+	var routes []RouteInfo
+	_ = o.Walk(func(route RouteInfo) error {
+		routes = append(routes, route)
+		return nil
+	})
+	return routes
+}
+
+// WriteRoutes writes the routes as JSON if the format is "json", otherwise as an aligned text table
+func WriteRoutes(w io.Writer, format string, routes []RouteInfo) error {
+	if format == "json" {
+		if routes == nil {
+			routes = []RouteInfo{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(routes)
+	}
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "API\tMETHOD\tPATH\tOPERATION\tBOUND\tWILDCARD\tPARAMS")
+	for _, route := range routes {
+		params := make([]string, len(route.Params))
+		for i, p := range route.Params {
+			params[i] = p.In + ":" + p.Name
+			if p.Required {
+				params[i] += "*"
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%t\t%t\t%s\n",
+			route.API, route.Method, route.Path, route.OperationID, route.Bound, route.Wildcard, strings.Join(params, ","),
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	// the empty last column leaves the padding of the previous one
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for _, line := range lines {
+		if _, err := io.WriteString(w, strings.TrimRight(line, " ")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RoutesHandler responds by the routes as JSON or as a text table,
+// the format is selected by the "format" query parameter or by the Accept header
+func RoutesHandler(routes func() []RouteInfo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = "json"
+			if strings.HasPrefix(r.Header.Get("Accept"), "text/plain") {
+				format = "text"
+			}
+		}
+		if format == "json" {
+			w.Header().Set("Content-Type", "application/json")
+		} else {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		}
+		if err := WriteRoutes(w, format, routes()); err != nil {
+			log.Println("Cannot write the routes:", err.Error())
+		}
+	}
+}
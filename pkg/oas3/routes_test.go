@@ -0,0 +1,213 @@
+package oas3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortedPaths(t *testing.T) {
+	t.Parallel()
+	paths := openapi3.Paths{}
+	for _, path := range []string{"/", "/users", "/users/{id}", "/users/me", "/users/{id}/profile", "/{type}/me", "/a"} {
+		paths[path] = &openapi3.PathItem{}
+	}
+	assert.Equal(t, []string{
+		"/users/me",
+		"/users/{id}/profile",
+		"/users/{id}",
+		"/users",
+		"/a",
+		"/",
+		"/{type}/me",
+	}, sortedPaths(paths))
+}
+
+func TestCheckPaths(t *testing.T) {
+	t.Parallel()
+	model, err := Load("testdata/routes/routes.yaml")
+	assert.NoError(t, err)
+	warnings, err := CheckPaths(model)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"path '/users/{id}/profile' shadows '/{type}/me/profile' for GET"}, warnings)
+
+	model, err = Load("testdata/routes/conflict.yaml")
+	assert.NoError(t, err)
+	_, err = CheckPaths(model)
+	assert.EqualError(t, err, "conflicting paths: paths '/users/{name}' and '/users/{id}' conflict for GET")
+	_, err = RegisterOperations(model, mux.NewRouter())
+	assert.Error(t, err)
+}
+
+func TestRegisterOperationsOrder(t *testing.T) {
+	t.Parallel()
+	model, err := Load("testdata/routes/routes.yaml")
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		router := mux.NewRouter()
+		mapper, err := RegisterOperations(model, router)
+		assert.NoError(t, err)
+		for _, id := range []string{"files.any", "files.get", "users.get", "users.me", "users.profile", "me.profile"} {
+			id := id
+			for _, route := range mapper.ByID(id).Routes {
+				route.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(id))
+				})
+			}
+		}
+		for url, expected := range map[string]string{
+			"/users/me":         "users.me",
+			"/users/1":          "users.get",
+			"/users/me/profile": "users.profile",
+			"/admin/me/profile": "me.profile",
+			"/files/a":          "files.get",
+			"/files/a/b":        "files.any",
+		} {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+			assert.Equal(t, expected, rec.Body.String(), url)
+		}
+	}
+}
+
+func TestMapperRoutes(t *testing.T) {
+	t.Parallel()
+	model, err := Load("testdata/routes/routes.yaml")
+	assert.NoError(t, err)
+	mapper, err := RegisterOperations(model, mux.NewRouter())
+	assert.NoError(t, err)
+	mapper.ByID("users.me").Handle(http.NotFoundHandler())
+	routes := mapper.Routes()
+	var ids []string
+	for _, route := range routes {
+		ids = append(ids, route.OperationID)
+	}
+	assert.Equal(t, []string{"files.get", "users.me", "users.profile", "users.get", "me.profile", "files.any"}, ids)
+	assert.Equal(t, RouteInfo{OperationID: "users.me", Method: http.MethodGet, Path: "/users/me", Bound: true}, routes[1])
+	assert.Equal(t, RouteInfo{
+		OperationID: "users.get",
+		Method:      http.MethodGet,
+		Path:        "/users/{id}",
+		Params:      []ParamInfo{{In: "path", Name: "id", Required: true, Type: "string"}},
+	}, routes[3])
+	assert.True(t, routes[5].Wildcard)
+
+	handler := RoutesHandler(mapper.Routes)
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/routes", nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var decoded []RouteInfo
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &decoded))
+	assert.Equal(t, routes, decoded)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/routes?format=text", nil),
+		func() *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "/routes", nil)
+			req.Header.Set("Accept", "text/plain")
+			return req
+		}(),
+	} {
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), "API  METHOD  PATH")
+		assert.Contains(t, rec.Body.String(), "GET     /users/me")
+	}
+}
+
+func TestTypedPathParams(t *testing.T) {
+	t.Parallel()
+	model, err := Load("testdata/routes/typed.yaml")
+	assert.NoError(t, err)
+
+	serve := func(router *mux.Router, mapper *Mapper, url string) (int, string) {
+		for _, id := range []string{"items.get", "items.tag", "users.get", "codes.get", "groups.get", "orders.get", "fallback"} {
+			id := id
+			for _, route := range mapper.ByID(id).Routes {
+				route.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(id))
+				})
+			}
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	router := mux.NewRouter()
+	mapper, err := RegisterOperations(model, router, WithTypedPathParams())
+	assert.NoError(t, err)
+	for url, expected := range map[string]string{
+		"/items/12":         "items.get",
+		"/items/-1":         "items.get",
+		"/items/abc":        "fallback",
+		"/items/1/tags/red": "items.tag",
+		"/items/1/tags/b.w": "items.tag",
+		"/users/123e4567-e89b-12d3-a456-426614174000": "users.get",
+		"/users/me":       "fallback",
+		"/codes/ABC.json": "codes.get",
+		"/groups/anyone":  "groups.get",
+		"/orders/a1":      "orders.get",
+	} {
+		status, body := serve(router, mapper, url)
+		assert.Equal(t, http.StatusOK, status, url)
+		assert.Equal(t, expected, body, url)
+	}
+	for _, url := range []string{"/items/1/tags/bxw", "/items/1/tags/blue", "/items/1.5/tags/red"} {
+		status, _ := serve(router, mapper, url)
+		assert.Equal(t, http.StatusNotFound, status, url)
+	}
+
+	router = mux.NewRouter()
+	mapper, err = RegisterOperations(model, router)
+	assert.NoError(t, err)
+	_, body := serve(router, mapper, "/items/abc")
+	assert.Equal(t, "items.get", body)
+}
+
+func TestSchemaRegexp(t *testing.T) {
+	t.Parallel()
+	assert.Empty(t, schemaRegexp(nil))
+	assert.Empty(t, schemaRegexp(&openapi3.Schema{Type: "string"}))
+	assert.Equal(t, booleanRe, schemaRegexp(&openapi3.Schema{Type: "boolean"}))
+	assert.Equal(t, numberRe, schemaRegexp(&openapi3.Schema{Type: "number"}))
+	assert.Equal(t, `1|2\.5`, schemaRegexp(&openapi3.Schema{Type: "number", Enum: []interface{}{1.0, 2.5}}))
+	assert.Empty(t, schemaRegexp(&openapi3.Schema{Type: "string", Pattern: "^(a|b)$"}))
+	assert.Equal(t, "(?:a|b)", schemaRegexp(&openapi3.Schema{Type: "string", Pattern: "^(?:a|b)$"}))
+	assert.Equal(t, "[0-9]+", schemaRegexp(&openapi3.Schema{Type: "string", Pattern: "^[0-9]+$"}))
+	for _, pattern := range []string{"[0-9]+", "^[0-9]+", "[0-9]+$", "^a|b$", `^a\$`, "^$"} {
+		assert.Empty(t, schemaRegexp(&openapi3.Schema{Type: "string", Pattern: pattern}), pattern)
+	}
+}
+
+func TestCheckRoutes(t *testing.T) {
+	t.Parallel()
+	route := func(api, method, path string, wildcard bool) RouteInfo {
+		return RouteInfo{API: api, OperationID: "op", Method: method, Path: path, Wildcard: wildcard}
+	}
+	assert.NoError(t, CheckRoutes([]RouteInfo{
+		route("v2", "GET", "/v2/users/{id}", false),
+		route("v2", "GET", "/v2/", true),
+		route("", "GET", "/v1/users/me", false),
+		route("", "POST", "/{version}/users/{id}", false),
+		route("", "GET", "/{version:v[0-9]+}/users/{id}", false),
+		route("", "GET", "/{version}/users/{id}/profile", false),
+	}))
+	err := CheckRoutes([]RouteInfo{
+		route("", "GET", "/", true),
+		route("v2", "GET", "/v2/users", false),
+	})
+	assert.EqualError(t, err, "unreachable routes: GET / (op) shadows GET /v2/users (op) of the API 'v2'")
+	err = CheckRoutes([]RouteInfo{
+		route("", "GET", "/{version}/users/{id}", false),
+		route("v2", "GET", "/v2/users/me", false),
+		route("v2", "GET", "/v2/users/", true),
+	})
+	assert.EqualError(t, err, "unreachable routes: GET /{version}/users/{id} (op) shadows GET /v2/users/me (op) of the API 'v2'")
+}
@@ -0,0 +1,77 @@
+package oas3
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// SecuritySchemeMutualTLS is the OpenAPI 3.1 security scheme type authenticating clients by certificates
+const SecuritySchemeMutualTLS = "mutualTLS"
+
+// validateModel validates the model,
+// the mutualTLS security schemes are skipped because they are unknown to OpenAPI 3.0 validator
+func validateModel(ctx context.Context, model *openapi3.Swagger) error {
+	skipped := make(map[string]*openapi3.SecuritySchemeRef)
+	for name, ref := range model.Components.SecuritySchemes {
+		if ref != nil && ref.Value != nil && ref.Value.Type == SecuritySchemeMutualTLS {
+			skipped[name] = ref
+			delete(model.Components.SecuritySchemes, name)
+		}
+	}
+	defer func() {
+		for name, ref := range skipped {
+			model.Components.SecuritySchemes[name] = ref
+		}
+	}()
+	return model.Validate(ctx)
+}
+
+func isMutualTLSScheme(model *openapi3.Swagger, name string) bool {
+	ref := model.Components.SecuritySchemes[name]
+	return ref != nil && ref.Value != nil && ref.Value.Type == SecuritySchemeMutualTLS
+}
+
+// requiresMutualTLS checks that every alternative of the operation security requirements
+// includes a mutualTLS scheme, so a request without a verified client certificate can't be authorized
+func requiresMutualTLS(model *openapi3.Swagger, operation *openapi3.Operation) bool {
+	security := model.Security
+	if operation.Security != nil {
+		security = *operation.Security
+	}
+	if len(security) == 0 {
+		return false
+	}
+	for _, requirement := range security {
+		found := false
+		for name := range requirement {
+			if isMutualTLSScheme(model, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ClientCertificate returns the verified client certificate of the request or nil
+func ClientCertificate(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
+}
+
+// ClientSubject returns the subject of the verified client certificate or an empty string
+func ClientSubject(r *http.Request) string {
+	cert := ClientCertificate(r)
+	if cert == nil {
+		return ""
+	}
+	return cert.Subject.String()
+}
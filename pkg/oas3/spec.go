@@ -0,0 +1,224 @@
+package oas3
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SVilgelm/oas3-server/pkg/utils"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/ghodss/yaml"
+)
+
+const (
+	specJSON = "json"
+	specYAML = "yaml"
+)
+
+// specFormats maps the media types to the formats of the model
+var specFormats = map[string]string{
+	"application/json":   specJSON,
+	"application/yaml":   specYAML,
+	"application/x-yaml": specYAML,
+	"text/yaml":          specYAML,
+	"text/x-yaml":        specYAML,
+}
+
+// specWildcards are the media ranges of the Accept header that accept any format of the model
+var specWildcards = map[string]bool{
+	"application/*": true,
+	"*/*":           true,
+}
+
+var specContentTypes = map[string]string{
+	specJSON: "application/json; charset=utf-8",
+	specYAML: "application/yaml; charset=utf-8",
+}
+
+// specDocument is the model marshaled to one format
+type specDocument struct {
+	data     []byte
+	gzipped  []byte
+	etag     string
+	gzipETag string
+}
+
+func newSpecDocument(data []byte) (*specDocument, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return &specDocument{
+		data:     data,
+		gzipped:  buf.Bytes(),
+		etag:     fmt.Sprintf(`"%x"`, sum[:16]),
+		gzipETag: fmt.Sprintf(`"%x-gzip"`, sum[:16]),
+	}, nil
+}
+
+// SpecOption configures the Spec
+type SpecOption func(s *Spec)
+
+// WithInternal publishes the objects marked as internal by the x-internal or x-visibility extensions
+func WithInternal() SpecOption {
+	return func(s *Spec) {
+		s.internal = true
+	}
+}
+
+// Spec serves the model as JSON or YAML,
+// the documents are marshaled and compressed once by Update instead of on every request
+type Spec struct {
+	internal bool
+
+	mu       sync.RWMutex
+	docs     map[string]*specDocument
+	modified time.Time
+}
+
+// NewSpec creates a Spec of the model
+func NewSpec(model *openapi3.Swagger, opts ...SpecOption) (*Spec, error) {
+	s := Spec{}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	if err := s.Update(model); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Update marshals the model again, it is used when the specification is reloaded,
+// the previous documents are served until the new ones are ready
+func (s *Spec) Update(model *openapi3.Swagger) error {
+	jsonData, err := json.Marshal(model)
+	if err != nil {
+		return err
+	}
+	if !s.internal {
+		jsonData, err = publicDocument(jsonData)
+		if err != nil {
+			return err
+		}
+	}
+	yamlData, err := yaml.JSONToYAML(jsonData)
+	if err != nil {
+		return err
+	}
+	docs := make(map[string]*specDocument, 2)
+	for format, data := range map[string][]byte{specJSON: jsonData, specYAML: yamlData} {
+		doc, err := newSpecDocument(data)
+		if err != nil {
+			return err
+		}
+		docs[format] = doc
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.docs = docs
+	s.modified = time.Now().UTC().Truncate(time.Second)
+	return nil
+}
+
+// document returns the JSON document of the model
+func (s *Spec) document() []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.docs[specJSON].data
+}
+
+// specFormat selects the format by the "format" query parameter, then by the Accept header,
+// then by the Content-Type header, JSON is used if none of them is set.
+// The wildcards of the Accept header like curl's "*/*" prefer no format, so the Content-Type header is used
+func specFormat(r *http.Request) (string, int) {
+	if format := strings.ToLower(r.URL.Query().Get("format")); format != "" {
+		switch format {
+		case specJSON, specYAML:
+			return format, 0
+		case "yml":
+			return specYAML, 0
+		}
+		return "", http.StatusNotAcceptable
+	}
+	if accept := r.Header.Get("Accept"); accept != "" {
+		wildcard := false
+		for _, a := range parseAccept(accept) {
+			if a.quality <= 0 {
+				continue
+			}
+			if format, ok := specFormats[a.value]; ok {
+				return format, 0
+			}
+			wildcard = wildcard || specWildcards[a.value]
+		}
+		if !wildcard {
+			return "", http.StatusNotAcceptable
+		}
+	}
+	if r.Header.Get("Content-Type") != "" {
+		for _, contentType := range utils.GetContentTypes(r) {
+			if format, ok := specFormats[contentType]; ok {
+				return format, 0
+			}
+		}
+		return "", http.StatusUnsupportedMediaType
+	}
+	return specJSON, 0
+}
+
+// ServeHTTP responds by the model in the selected format, gzipped if the client accepts it,
+// the conditional requests are answered by 304 Not Modified
+func (s *Spec) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", "Accept, Accept-Encoding")
+	format, status := specFormat(r)
+	if status != 0 {
+		http.Error(w, "Supported media type not found. Use 'application/json' or 'application/yaml'", status)
+		return
+	}
+	s.mu.RLock()
+	doc, modified := s.docs[format], s.modified
+	s.mu.RUnlock()
+
+	data, etag := doc.data, doc.etag
+	if acceptsEncoding(r.Header.Get("Accept-Encoding"), "gzip") && w.Header().Get("Content-Encoding") == "" {
+		data, etag = doc.gzipped, doc.gzipETag
+		w.Header().Set("Content-Encoding", "gzip")
+	}
+	w.Header().Set("Content-Type", specContentTypes[format])
+	w.Header().Set("ETag", etag)
+	http.ServeContent(w, r, "", modified, bytes.NewReader(data))
+}
+
+// itemSpec returns the Spec of the operation, the model is marshaled if the operation has no Spec
+func itemSpec(item *Item) (*Spec, error) {
+	if item.Spec != nil {
+		return item.Spec, nil
+	}
+	return NewSpec(item.Model)
+}
+
+// Model serves the model of the current operation like Spec, the Spec of the operation is served if it is set,
+// otherwise the model is marshaled on every request
+func Model(w http.ResponseWriter, r *http.Request) {
+	spec, err := itemSpec(OperationFromContext(r.Context()))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	spec.ServeHTTP(w, r)
+}
@@ -0,0 +1,248 @@
+package oas3
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcceptsEncoding(t *testing.T) {
+	t.Parallel()
+	for header, expected := range map[string]bool{
+		"":                     false,
+		"gzip":                 true,
+		"deflate, gzip;q=0.5":  true,
+		"GZIP":                 true,
+		"br":                   false,
+		"*":                    true,
+		"gzip;q=0, *":          false,
+		"identity;q=1, *;q=0":  false,
+		"deflate;q=1, *;q=0.1": true,
+	} {
+		assert.Equal(t, expected, acceptsEncoding(header, "gzip"), header)
+	}
+}
+
+func TestSpecFormat(t *testing.T) {
+	t.Parallel()
+	model, err := Load("testdata/spec/internal.yaml")
+	assert.NoError(t, err)
+	spec, err := NewSpec(model)
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		url         string
+		header      string
+		value       string
+		status      int
+		contentType string
+	}{
+		{"/", "", "", http.StatusOK, "application/json; charset=utf-8"},
+		{"/?format=yaml", "Accept", "application/json", http.StatusOK, "application/yaml; charset=utf-8"},
+		{"/?format=yml", "", "", http.StatusOK, "application/yaml; charset=utf-8"},
+		{"/?format=json", "", "", http.StatusOK, "application/json; charset=utf-8"},
+		{"/?format=xml", "", "", http.StatusNotAcceptable, ""},
+		{"/", "Accept", "application/yaml", http.StatusOK, "application/yaml; charset=utf-8"},
+		{"/", "Accept", "application/json;q=0.5, text/yaml", http.StatusOK, "application/yaml; charset=utf-8"},
+		{"/", "Accept", "text/html, */*;q=0.8", http.StatusOK, "application/json; charset=utf-8"},
+		{"/", "Accept", "text/html", http.StatusNotAcceptable, ""},
+		{"/", "Content-Type", "application/yaml", http.StatusOK, "application/yaml; charset=utf-8"},
+		{"/", "Content-Type", "application/json", http.StatusOK, "application/json; charset=utf-8"},
+		{"/", "Content-Type", "application/pdf", http.StatusUnsupportedMediaType, ""},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+		if tc.header != "" {
+			req.Header.Set(tc.header, tc.value)
+		}
+		rec := httptest.NewRecorder()
+		spec.ServeHTTP(rec, req)
+		assert.Equal(t, tc.status, rec.Code, tc)
+		if tc.contentType != "" {
+			assert.Equal(t, tc.contentType, rec.Header().Get("Content-Type"), tc)
+		}
+	}
+
+	for _, tc := range []struct {
+		accept      string
+		requestType string
+		status      int
+		contentType string
+	}{
+		{"*/*", "application/yaml", http.StatusOK, "application/yaml; charset=utf-8"},
+		{"application/*", "application/yaml", http.StatusOK, "application/yaml; charset=utf-8"},
+		{"*/*", "application/pdf", http.StatusUnsupportedMediaType, ""},
+		{"application/json, */*", "application/yaml", http.StatusOK, "application/json; charset=utf-8"},
+		{"*/*;q=0", "application/yaml", http.StatusNotAcceptable, ""},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", tc.accept)
+		req.Header.Set("Content-Type", tc.requestType)
+		rec := httptest.NewRecorder()
+		spec.ServeHTTP(rec, req)
+		assert.Equal(t, tc.status, rec.Code, tc)
+		if tc.contentType != "" {
+			assert.Equal(t, tc.contentType, rec.Header().Get("Content-Type"), tc)
+		}
+	}
+}
+
+func TestSpecCaching(t *testing.T) {
+	t.Parallel()
+	model, err := Load("testdata/spec/internal.yaml")
+	assert.NoError(t, err)
+	spec, err := NewSpec(model)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	spec.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.NotEmpty(t, rec.Header().Get("Last-Modified"))
+	assert.Equal(t, "Accept, Accept-Encoding", rec.Header().Get("Vary"))
+	body := rec.Body.Bytes()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", `"other", `+etag)
+	rec = httptest.NewRecorder()
+	spec.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/?format=yaml", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	spec.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+	spec.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	zr, err := gzip.NewReader(rec.Body)
+	assert.NoError(t, err)
+	data, err := ioutil.ReadAll(zr)
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(body, data))
+
+	model.Info.Version = "2.0.0"
+	assert.NoError(t, spec.Update(model))
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	spec.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"version":"2.0.0"`)
+}
+
+func TestModel(t *testing.T) {
+	t.Parallel()
+	model, err := Load("testdata/spec/internal.yaml")
+	assert.NoError(t, err)
+	get := func(item *Item) string {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		Model(rec, req.WithContext(WithOperation(context.Background(), item)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEmpty(t, rec.Header().Get("ETag"))
+		return rec.Body.String()
+	}
+
+	body := get(&Item{Model: model})
+	assert.Contains(t, body, `"operationId":"items.list"`)
+	assert.NotContains(t, body, "items.purge")
+
+	spec, err := NewSpec(model, WithInternal())
+	assert.NoError(t, err)
+	body = get(&Item{Model: model, Spec: spec})
+	assert.Contains(t, body, `"operationId":"items.purge"`)
+}
+
+func TestSpecInternal(t *testing.T) {
+	t.Parallel()
+	model, err := Load("testdata/spec/internal.yaml")
+	assert.NoError(t, err)
+
+	get := func(spec *Spec) string {
+		rec := httptest.NewRecorder()
+		spec.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?format=yaml", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+
+	spec, err := NewSpec(model)
+	assert.NoError(t, err)
+	public := get(spec)
+	assert.Contains(t, public, "items.list")
+	assert.Contains(t, public, "- name: items")
+	assert.NotContains(t, public, "items.purge")
+	assert.NotContains(t, public, "admin.reindex")
+	assert.NotContains(t, public, "/admin/reindex")
+	assert.NotNil(t, model.Paths["/items"].Delete, "the model must not be modified")
+
+	spec, err = NewSpec(model, WithInternal())
+	assert.NoError(t, err)
+	internal := get(spec)
+	assert.Contains(t, internal, "items.purge")
+	assert.Contains(t, internal, "admin.reindex")
+}
+
+func TestSpecVisibility(t *testing.T) {
+	t.Parallel()
+	model, err := Load("testdata/spec/visibility.yaml")
+	assert.NoError(t, err)
+	spec, err := NewSpec(model)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	spec.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	doc, err := decodeDocument(rec.Body.Bytes())
+	assert.NoError(t, err)
+
+	pathItem := lookup(doc, "#/paths/~1users~1{id}")
+	assert.NotNil(t, pathItem["get"])
+	assert.Nil(t, pathItem["delete"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"$ref": "#/components/parameters/ID"},
+	}, pathItem["parameters"])
+	params := lookup(doc, "#/paths/~1users~1{id}/get")["parameters"].([]interface{})
+	assert.Len(t, params, 1)
+	assert.Equal(t, "fields", params[0].(map[string]interface{})["name"])
+
+	user := lookup(doc, "#/components/schemas/User")
+	assert.Equal(t, []interface{}{"id", "name"}, user["required"])
+	assert.Len(t, user["properties"], 3)
+	assert.Nil(t, lookup(doc, "#/components/schemas/User/properties/password_hash"))
+	assert.Nil(t, lookup(doc, "#/components/schemas/User/properties/audit"))
+	assert.NotNil(t, lookup(doc, "#/components/schemas/User/properties/flags/properties/beta"))
+	assert.Nil(t, lookup(doc, "#/components/schemas/User/properties/flags/properties/shard"))
+
+	for _, ref := range []string{
+		"#/components/schemas/Audit",
+		"#/components/schemas/Report",
+		"#/components/schemas/Entry",
+		"#/components/parameters/Trace",
+		"#/components/securitySchemes",
+	} {
+		assert.Nil(t, lookup(doc, ref), ref)
+	}
+	assert.NotNil(t, lookup(doc, "#/components/schemas/Standalone"), "the unused components are kept")
+	assert.NotNil(t, lookup(doc, "#/components/parameters/ID"))
+
+	mapper, err := RegisterOperations(model, mux.NewRouter())
+	assert.NoError(t, err)
+	assert.NotNil(t, mapper.ByID("users.delete"), "the internal operations are routed")
+	assert.NotNil(t, model.Components.Schemas["User"].Value.Properties["password_hash"])
+}
@@ -0,0 +1,166 @@
+package oas3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// getDurationExt returns the value of the duration extension, a string like "1m30s" or a number of seconds
+func getDurationExt(name string, extensions map[string]interface{}) (time.Duration, bool, error) {
+	raw, ok := extensions[name].(json.RawMessage)
+	if !ok {
+		return 0, false, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return 0, false, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	switch v := value.(type) {
+	case float64:
+		return time.Duration(v * float64(time.Second)), true, nil
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid %s: %v", name, err)
+		}
+		return d, true, nil
+	}
+	return 0, false, fmt.Errorf("invalid %s: a duration like \"1m30s\" or a number of seconds is required", name)
+}
+
+func operationTimeout(extensions map[string]interface{}, def time.Duration) (time.Duration, error) {
+	timeout, ok, err := getDurationExt("x-timeout", extensions)
+	if err != nil || !ok {
+		return def, err
+	}
+	return timeout, nil
+}
+
+func timeoutError(operationID string, timeout time.Duration) *Error {
+	return &Error{
+		Status:    http.StatusGatewayTimeout,
+		Code:      "timeout",
+		Message:   fmt.Sprintf("the operation did not complete in %s", timeout),
+		Operation: operationID,
+	}
+}
+
+// timeoutWriter passes the response of the handler until the timeout responds by the error,
+// the handler has its own headers, so it can't race with the error response
+type timeoutWriter struct {
+	ctx      context.Context
+	w        http.ResponseWriter
+	h        http.Header
+	mu       sync.Mutex
+	started  bool
+	timedOut bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.h
+}
+
+// expired checks if the deadline passed before the response started, it must be called under the lock
+func (tw *timeoutWriter) expired() bool {
+	if !tw.started && !tw.timedOut && tw.ctx.Err() == context.DeadlineExceeded {
+		tw.timedOut = true
+	}
+	return tw.timedOut
+}
+
+// start sends the headers of the handler, it must be called under the lock
+func (tw *timeoutWriter) start(status int) {
+	if tw.started {
+		return
+	}
+	tw.started = true
+	dst := tw.w.Header()
+	for name, values := range tw.h {
+		dst[name] = values
+	}
+	tw.w.WriteHeader(status)
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.expired() {
+		tw.start(status)
+	}
+}
+
+func (tw *timeoutWriter) Write(body []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.expired() {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.start(http.StatusOK)
+	return tw.w.Write(body)
+}
+
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.expired() {
+		return
+	}
+	tw.start(http.StatusOK)
+	if f, ok := tw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// serveWithTimeout runs the handler with the deadline in the context of the request.
+// If the handler has not started the response before the deadline, the 504 error is responded
+// and the later writes of the handler fail by http.ErrHandlerTimeout,
+// otherwise the started response is completed by the handler.
+func serveWithTimeout(w http.ResponseWriter, r *http.Request, next http.Handler, operationID string, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	r = r.WithContext(ctx)
+	tw := timeoutWriter{
+		ctx: ctx,
+		w:   w,
+		h:   make(http.Header),
+	}
+	done := make(chan struct{})
+	panicked := make(chan interface{}, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				panicked <- p
+			}
+		}()
+		next.ServeHTTP(&tw, r)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case p := <-panicked:
+		panic(p)
+	case <-ctx.Done():
+		tw.mu.Lock()
+		timedOut := tw.expired()
+		tw.mu.Unlock()
+		if !timedOut {
+			// the response is started or the client is gone, the handler completes it
+			select {
+			case <-done:
+			case p := <-panicked:
+				panic(p)
+			}
+		}
+	}
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.expired() {
+		WriteError(w, timeoutError(operationID, timeout))
+		return
+	}
+	tw.start(http.StatusOK)
+}
@@ -0,0 +1,114 @@
+package oas3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDurationExt(t *testing.T) {
+	t.Parallel()
+	for raw, expected := range map[string]time.Duration{
+		`"1m30s"`: 90 * time.Second,
+		`"50ms"`:  50 * time.Millisecond,
+		`2`:       2 * time.Second,
+		`0.5`:     500 * time.Millisecond,
+	} {
+		d, ok, err := getDurationExt("x-timeout", map[string]interface{}{"x-timeout": json.RawMessage(raw)})
+		assert.NoError(t, err, raw)
+		assert.True(t, ok, raw)
+		assert.Equal(t, expected, d, raw)
+	}
+	_, ok, err := getDurationExt("x-timeout", nil)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	_, _, err = getDurationExt("x-timeout", map[string]interface{}{"x-timeout": json.RawMessage(`true`)})
+	assert.Error(t, err)
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+	model, err := Load("testdata/timeouts/invalid.yaml")
+	assert.NoError(t, err)
+	_, err = RegisterOperations(model, mux.NewRouter())
+	assert.EqualError(t, err, `operation 'lookup': invalid x-timeout: time: invalid duration "soon"`)
+
+	model, err = Load("testdata/timeouts/timeouts.yaml")
+	assert.NoError(t, err)
+
+	for _, validation := range []bool{false, true} {
+		router := mux.NewRouter()
+		mapper, err := RegisterOperations(model, router, WithTimeout(time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Minute, mapper.MaxTimeout())
+		router.Use(Middleware(mapper, false, validation))
+
+		lateWrite := make(chan error, 1)
+		mapper.ByID("lookup").Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			deadline, ok := r.Context().Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(50*time.Millisecond), deadline, 50*time.Millisecond)
+			if r.URL.Query().Get("slow") == "" {
+				w.Header().Set("X-Found", "true")
+				_, _ = w.Write([]byte("found"))
+				return
+			}
+			<-r.Context().Done()
+			w.Header().Set("X-Found", "false")
+			_, err := w.Write([]byte("late"))
+			lateWrite <- err
+		}))
+		mapper.ByID("stream").Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("partial"))
+			<-r.Context().Done()
+			_, _ = w.Write([]byte(" done"))
+		}))
+		mapper.ByID("export").Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			deadline, ok := r.Context().Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		serve := func(url string) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+			return rec
+		}
+
+		rec := serve("/lookup")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "true", rec.Header().Get("X-Found"))
+		assert.Equal(t, "found", rec.Body.String())
+
+		rec = serve("/lookup?slow=1")
+		assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+		assert.Empty(t, rec.Header().Get("X-Found"))
+		var e Error
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &e))
+		assert.Equal(t, *timeoutError("lookup", 50*time.Millisecond), e)
+		assert.Equal(t, http.ErrHandlerTimeout, <-lateWrite)
+
+		rec = serve("/stream")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "partial done", rec.Body.String())
+
+		rec = serve("/export")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	}
+}
+
+func TestTimeoutPanic(t *testing.T) {
+	t.Parallel()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	assert.PanicsWithValue(t, "boom", func() {
+		serveWithTimeout(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), handler, "op", time.Second)
+	})
+}
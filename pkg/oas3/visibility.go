@@ -0,0 +1,330 @@
+package oas3
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// maxRefDepth limits the chains of the references resolved by the publisher
+const maxRefDepth = 16
+
+var operationKeys = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// isHidden checks if the object of the document is marked as internal
+// by "x-internal: true" or by "x-visibility: internal"
+func isHidden(obj map[string]interface{}) bool {
+	if obj == nil {
+		return false
+	}
+	if internal, ok := obj["x-internal"].(bool); ok && internal {
+		return true
+	}
+	visibility, _ := obj["x-visibility"].(string)
+	return strings.EqualFold(visibility, "internal")
+}
+
+func unescapeRef(part string) string {
+	return strings.Replace(strings.Replace(part, "~1", "/", -1), "~0", "~", -1)
+}
+
+// lookup returns the object of the document by the local reference, e.g. "#/components/schemas/Item"
+func lookup(doc map[string]interface{}, ref string) map[string]interface{} {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+	var node interface{} = doc
+	for _, part := range strings.Split(ref[2:], "/") {
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		node = obj[unescapeRef(part)]
+	}
+	obj, _ := node.(map[string]interface{})
+	return obj
+}
+
+// publisher removes the internal operations, parameters and properties from the JSON document of the model
+type publisher struct {
+	doc map[string]interface{}
+}
+
+// resolve follows the local references of the object
+func (p *publisher) resolve(obj map[string]interface{}) map[string]interface{} {
+	for i := 0; i < maxRefDepth && obj != nil; i++ {
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return obj
+		}
+		obj = lookup(p.doc, ref)
+	}
+	return obj
+}
+
+// hidden checks the object and the object referred by it
+func (p *publisher) hidden(value interface{}) bool {
+	obj, _ := value.(map[string]interface{})
+	return isHidden(obj) || isHidden(p.resolve(obj))
+}
+
+func (p *publisher) schema(value interface{}) {
+	schema, ok := value.(map[string]interface{})
+	if !ok || schema["$ref"] != nil {
+		return
+	}
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, property := range properties {
+			if p.hidden(property) {
+				delete(properties, name)
+				continue
+			}
+			p.schema(property)
+		}
+		if required, ok := schema["required"].([]interface{}); ok {
+			visible := required[:0]
+			for _, name := range required {
+				if name, ok := name.(string); ok && properties[name] != nil {
+					visible = append(visible, name)
+				}
+			}
+			if len(visible) > 0 {
+				schema["required"] = visible
+			} else {
+				delete(schema, "required")
+			}
+		}
+	}
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		p.schema(schema[key])
+	}
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		if schemas, ok := schema[key].([]interface{}); ok {
+			for _, s := range schemas {
+				p.schema(s)
+			}
+		}
+	}
+}
+
+func (p *publisher) content(value interface{}) {
+	content, _ := value.(map[string]interface{})
+	for _, media := range content {
+		if media, ok := media.(map[string]interface{}); ok {
+			p.schema(media["schema"])
+		}
+	}
+}
+
+// parameters removes the internal parameters of the list
+func (p *publisher) parameters(obj map[string]interface{}) {
+	params, ok := obj["parameters"].([]interface{})
+	if !ok {
+		return
+	}
+	visible := make([]interface{}, 0, len(params))
+	for _, param := range params {
+		if p.hidden(param) {
+			continue
+		}
+		visible = append(visible, param)
+		if param, ok := param.(map[string]interface{}); ok {
+			p.schema(param["schema"])
+			p.content(param["content"])
+		}
+	}
+	if len(visible) > 0 {
+		obj["parameters"] = visible
+	} else {
+		delete(obj, "parameters")
+	}
+}
+
+// message processes the request body, the response or the header
+func (p *publisher) message(value interface{}) {
+	if obj, ok := value.(map[string]interface{}); ok {
+		p.schema(obj["schema"])
+		p.content(obj["content"])
+		headers, _ := obj["headers"].(map[string]interface{})
+		for _, header := range headers {
+			p.message(header)
+		}
+	}
+}
+
+func (p *publisher) operation(operation map[string]interface{}) {
+	p.parameters(operation)
+	p.message(operation["requestBody"])
+	responses, _ := operation["responses"].(map[string]interface{})
+	for _, response := range responses {
+		p.message(response)
+	}
+}
+
+func (p *publisher) paths() {
+	paths, _ := p.doc["paths"].(map[string]interface{})
+	for path, value := range paths {
+		pathItem, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		operations, hidden := 0, 0
+		for _, method := range operationKeys {
+			operation, ok := pathItem[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if isHidden(operation) {
+				delete(pathItem, method)
+				hidden++
+				continue
+			}
+			operations++
+			p.operation(operation)
+		}
+		if operations == 0 && hidden > 0 {
+			delete(paths, path)
+			continue
+		}
+		p.parameters(pathItem)
+	}
+}
+
+func (p *publisher) components() {
+	components, _ := p.doc["components"].(map[string]interface{})
+	for kind, value := range components {
+		items, _ := value.(map[string]interface{})
+		for _, item := range items {
+			switch kind {
+			case "schemas":
+				p.schema(item)
+			case "parameters", "requestBodies", "responses", "headers":
+				p.message(item)
+			}
+		}
+	}
+}
+
+// collectRefs calls the function for every reference of the value
+func collectRefs(value interface{}, fn func(ref string)) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			fn(ref)
+		}
+		for _, item := range v {
+			collectRefs(item, fn)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectRefs(item, fn)
+		}
+	}
+}
+
+// securityRefs calls the function for the security schemes required by the model and by the operations
+func securityRefs(doc map[string]interface{}, fn func(ref string)) {
+	requirements := []interface{}{doc["security"]}
+	paths, _ := doc["paths"].(map[string]interface{})
+	for _, pathItem := range paths {
+		pathItem, _ := pathItem.(map[string]interface{})
+		for _, method := range operationKeys {
+			if operation, ok := pathItem[method].(map[string]interface{}); ok {
+				requirements = append(requirements, operation["security"])
+			}
+		}
+	}
+	for _, value := range requirements {
+		list, _ := value.([]interface{})
+		for _, requirement := range list {
+			requirement, _ := requirement.(map[string]interface{})
+			for name := range requirement {
+				fn("#/components/securitySchemes/" + strings.Replace(strings.Replace(name, "~", "~0", -1), "/", "~1", -1))
+			}
+		}
+	}
+}
+
+// reachable returns the local references used by the document outside of the components
+// and the references used by the referred components
+func reachable(doc map[string]interface{}) map[string]bool {
+	refs := make(map[string]bool)
+	var queue []interface{}
+	add := func(ref string) {
+		if refs[ref] {
+			return
+		}
+		refs[ref] = true
+		if target := lookup(doc, ref); target != nil {
+			queue = append(queue, target)
+		}
+	}
+	for key, value := range doc {
+		if key != "components" {
+			collectRefs(value, add)
+		}
+	}
+	securityRefs(doc, add)
+	for len(queue) > 0 {
+		value := queue[0]
+		queue = queue[1:]
+		collectRefs(value, add)
+	}
+	return refs
+}
+
+// prune removes the components referred before the hiding and not referred after it,
+// the components not used by the original document are kept
+func (p *publisher) prune(used map[string]bool) {
+	components, _ := p.doc["components"].(map[string]interface{})
+	if components == nil {
+		return
+	}
+	still := reachable(p.doc)
+	for ref := range used {
+		if still[ref] || !strings.HasPrefix(ref, "#/components/") {
+			continue
+		}
+		parts := strings.Split(ref[len("#/components/"):], "/")
+		if len(parts) != 2 {
+			continue
+		}
+		kind := unescapeRef(parts[0])
+		if items, ok := components[kind].(map[string]interface{}); ok {
+			delete(items, unescapeRef(parts[1]))
+			if len(items) == 0 {
+				delete(components, kind)
+			}
+		}
+	}
+}
+
+func decodeDocument(data []byte) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// publicDocument removes from the JSON document of the model the operations, the parameters
+// and the schema properties marked by "x-internal: true" or "x-visibility: internal",
+// the paths without operations and the components used only by the removed objects are removed too
+func publicDocument(data []byte) ([]byte, error) {
+	original, err := decodeDocument(data)
+	if err != nil || original == nil {
+		return data, err
+	}
+	used := reachable(original)
+	doc, err := decodeDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	p := publisher{doc: doc}
+	p.paths()
+	p.components()
+	p.prune(used)
+	return json.Marshal(doc)
+}
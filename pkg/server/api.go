@@ -0,0 +1,170 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+
+	"github.com/SVilgelm/oas3-server/pkg/config"
+	"github.com/SVilgelm/oas3-server/pkg/oas3"
+	"github.com/SVilgelm/oas3-server/pkg/tracing"
+)
+
+// API is a specification served by the Server with its own handlers and base path,
+// all APIs share the middlewares, the logging and the listeners of the Server
+type API struct {
+	Name   string
+	Model  *openapi3.Swagger
+	R      *mux.Router
+	mapper *oas3.Mapper
+	spec   *oas3.Spec
+}
+
+// HandleFunc links the handler with the operation
+func (a *API) HandleFunc(operationID string, handler http.HandlerFunc) error {
+	return a.Handle(operationID, handler)
+}
+
+// Handle links the handler with the operation
+func (a *API) Handle(operationID string, handler http.Handler) error {
+	if a == nil {
+		return fmt.Errorf("the API for the operation '%s' not found", operationID)
+	}
+	item := a.mapper.ByID(operationID)
+	if item == nil {
+		return fmt.Errorf("the operation '%s' not found", operationID)
+	}
+	if a.Name == "" {
+		log.Printf("Linking new handler for the operation '%s'", operationID)
+	} else {
+		log.Printf("Linking new handler for the operation '%s' of the API '%s'", operationID, a.Name)
+	}
+	item.Handle(handler)
+	return nil
+}
+
+// MockUnbound links the mock handlers with all operations without handlers,
+// the mocks respond by the examples of the successful responses or by the values generated from their schemas
+func (a *API) MockUnbound() []string {
+	var mocked []string
+	for _, id := range a.mapper.IDs() {
+		item := a.mapper.ByID(id)
+		if item.Bound() {
+			continue
+		}
+		for _, route := range item.Routes {
+			if op := item.Operation(route); op != nil {
+				route.Handler(oas3.MockHandler(op))
+			}
+		}
+		mocked = append(mocked, id)
+		log.Printf("Mocking the operation '%s'", id)
+	}
+	return mocked
+}
+
+// Spec returns the model served by the oas3.model and oas3.console operations, nil if the model has none of them,
+// Spec.Update publishes the changes of the model
+func (a *API) Spec() *oas3.Spec {
+	return a.spec
+}
+
+// addSpec creates the Spec of the model once and links it with the oas3.model and oas3.console operations
+func (a *API) addSpec(opts ...oas3.SpecOption) error {
+	model, console := a.mapper.ByID("oas3.model"), a.mapper.ByID("oas3.console")
+	if model == nil && console == nil {
+		return nil
+	}
+	spec, err := oas3.NewSpec(a.Model, opts...)
+	if err != nil {
+		return err
+	}
+	a.spec = spec
+	if model != nil {
+		model.Spec = spec
+		_ = a.Handle("oas3.model", spec)
+	}
+	if console != nil {
+		console.Spec = spec
+		_ = a.HandleFunc("oas3.console", oas3.ConsoleHandler(a.mapper.BasePath(), spec))
+	}
+	return nil
+}
+
+// Mapper returns the operations of the API
+func (a *API) Mapper() *oas3.Mapper {
+	return a.mapper
+}
+
+// basePathDepth returns the number of the segments of the base path of the API
+func basePathDepth(cfg config.API) int {
+	path := oas3.NormalizeBasePath(cfg.BasePath)
+	if cfg.BasePath == "" {
+		path, _ = oas3.BasePath(cfg.Model)
+	}
+	return strings.Count(path, "/")
+}
+
+// newAPI registers the operations of the model on a new subrouter of the server router
+func (s *Server) newAPI(name string, model *openapi3.Swagger, basePath string) (*API, error) {
+	api := API{
+		Name:  name,
+		Model: model,
+		R:     s.R.NewRoute().Subrouter(),
+	}
+	var opts []oas3.Option
+	if basePath != "" {
+		opts = append(opts, oas3.WithBasePath(basePath))
+	}
+	if s.Config.Validate.PathParams {
+		opts = append(opts, oas3.WithTypedPathParams())
+	}
+	if s.Config.MaxBodySize > 0 {
+		opts = append(opts, oas3.WithMaxBodySize(s.Config.MaxBodySize))
+	}
+	if timeout := s.Config.Timeout.Duration(); timeout > 0 {
+		opts = append(opts, oas3.WithTimeout(timeout))
+	}
+	mapper, err := oas3.RegisterOperations(model, api.R, opts...)
+	if err != nil {
+		if name != "" {
+			return nil, fmt.Errorf("api '%s': %s", name, err.Error())
+		}
+		return nil, err
+	}
+	api.mapper = mapper
+	if s.exporter != nil {
+		api.R.Use(tracing.Middleware(api.mapper, s.exporter))
+	}
+	api.R.Use(LogHTTP(api.mapper))
+	if s.Config.Compress.Enabled {
+		compress, err := oas3.Compress(api.mapper, s.Config.Compress.MinSize, s.Config.Compress.Level)
+		if err != nil {
+			return nil, err
+		}
+		api.R.Use(compress)
+	}
+	api.R.Use(oas3.Middleware(
+		api.mapper,
+		s.Config.Validate.Request,
+		s.Config.Validate.Response,
+	))
+	var specOpts []oas3.SpecOption
+	if s.Config.Publish.Internal {
+		specOpts = append(specOpts, oas3.WithInternal())
+	}
+	if err := api.addSpec(specOpts...); err != nil {
+		return nil, err
+	}
+	_ = api.HandleFunc("oas3.routes", oas3.RoutesHandler(s.Routes))
+	if _, err := os.Stat(s.Config.Static); !os.IsNotExist(err) && api.mapper.ByID("static") != nil {
+		fileServer := http.FileServer(FileSystem{http.Dir(s.Config.Static)})
+		_ = api.Handle("static", fileServer)
+	}
+	return &api, nil
+}
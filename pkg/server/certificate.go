@@ -0,0 +1,123 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// certPollInterval is an interval to check the modification time of the certificate files
+var certPollInterval = 10 * time.Second
+
+// certReloader provides the certificate to TLS handshakes and reloads it
+// when the files change or on SIGHUP, keeping the old one if the new pair is invalid
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time
+
+	stop chan struct{}
+	once sync.Once
+}
+
+func loadCertificate(certFile, keyFile string) (*tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if cert.Leaf == nil {
+		cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &cert, nil
+}
+
+func lastModified(files ...string) time.Time {
+	var res time.Time
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil && info.ModTime().After(res) {
+			res = info.ModTime()
+		}
+	}
+	return res
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	cert, err := loadCertificate(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS certificate or key: %s", err.Error())
+	}
+	return &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		cert:     cert,
+		modTime:  lastModified(certFile, keyFile),
+		stop:     make(chan struct{}),
+	}, nil
+}
+
+// GetCertificate returns the current certificate
+func (c *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cert, nil
+}
+
+func (c *certReloader) reload() error {
+	modTime := lastModified(c.certFile, c.keyFile)
+	cert, err := loadCertificate(c.certFile, c.keyFile)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.modTime = modTime
+	if err != nil {
+		log.Printf("Cannot reload TLS certificate '%s', keeping the old one: %s", c.certFile, err.Error())
+		return err
+	}
+	c.cert = cert
+	log.Printf("Reloaded TLS certificate '%s', expires at %s", c.certFile, cert.Leaf.NotAfter)
+	return nil
+}
+
+func (c *certReloader) changed() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !lastModified(c.certFile, c.keyFile).Equal(c.modTime)
+}
+
+// watch reloads the certificate on SIGHUP or when the files change until stopped
+func (c *certReloader) watch() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	ticker := time.NewTicker(certPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-hup:
+			_ = c.reload()
+		case <-ticker.C:
+			if c.changed() {
+				_ = c.reload()
+			}
+		}
+	}
+}
+
+func (c *certReloader) close() {
+	c.once.Do(func() {
+		close(c.stop)
+	})
+}
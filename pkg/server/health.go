@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	healthStatusOK   = "ok"
+	healthStatusFail = "fail"
+)
+
+// HealthCheck checks a health of a component, returns nil if the component is healthy
+type HealthCheck func(ctx context.Context) error
+
+type healthCheckResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type healthReport struct {
+	Status string              `json:"status"`
+	Checks []healthCheckResult `json:"checks"`
+}
+
+type health struct {
+	mu       sync.RWMutex
+	names    []string
+	checks   map[string]HealthCheck
+	stopping int32
+}
+
+func newHealth() *health {
+	return &health{
+		checks: make(map[string]HealthCheck),
+	}
+}
+
+func (h *health) add(name string, check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.checks[name]; !ok {
+		h.names = append(h.names, name)
+	}
+	h.checks[name] = check
+}
+
+func (h *health) setStopping() {
+	atomic.StoreInt32(&h.stopping, 1)
+}
+
+func (h *health) isStopping() bool {
+	return atomic.LoadInt32(&h.stopping) == 1
+}
+
+func (h *health) run(ctx context.Context) *healthReport {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	report := healthReport{
+		Status: healthStatusOK,
+		Checks: make([]healthCheckResult, 0, len(h.names)),
+	}
+	for _, name := range h.names {
+		res := healthCheckResult{Name: name, Status: healthStatusOK}
+		if err := h.checks[name](ctx); err != nil {
+			res.Status = healthStatusFail
+			res.Error = err.Error()
+			report.Status = healthStatusFail
+		}
+		report.Checks = append(report.Checks, res)
+	}
+	return &report
+}
+
+func writeHealthReport(w http.ResponseWriter, report *healthReport) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status == healthStatusOK {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if _, err := w.Write(data); err != nil {
+		log.Print(err)
+	}
+}
+
+// liveness reports that the process is alive, the registered checks are not run,
+// so a failing dependency doesn't restart the healthy process
+func (h *health) liveness(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, &healthReport{
+		Status: healthStatusOK,
+		Checks: []healthCheckResult{},
+	})
+}
+
+// readiness reports the status of all registered checks and fails during shutdown
+func (h *health) readiness(w http.ResponseWriter, r *http.Request) {
+	report := h.run(r.Context())
+	if h.isStopping() {
+		report.Status = healthStatusFail
+		report.Checks = append(report.Checks, healthCheckResult{
+			Name:   "shutdown",
+			Status: healthStatusFail,
+			Error:  "the server is shutting down",
+		})
+	}
+	writeHealthReport(w, report)
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/SVilgelm/oas3-server/pkg/config"
+)
+
+type listener struct {
+	cfg      config.Listener
+	ln       net.Listener
+	server   *http.Server
+	url      string
+	reloader *certReloader
+}
+
+func loadClientCAs(fileName string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in '%s'", fileName)
+	}
+	return pool, nil
+}
+
+// newTLSConfig loads the certificates and the key to validate them before serving,
+// the server certificate is provided by the reloader
+func newTLSConfig(cfg config.TLS, reloader *certReloader) (*tls.Config, error) {
+	var err error
+	tlsCfg := tls.Config{
+		GetCertificate: reloader.GetCertificate,
+		NextProtos:     []string{"h2", "http/1.1"},
+	}
+	if tlsCfg.ClientAuth, err = cfg.ClientAuthType(); err != nil {
+		return nil, err
+	}
+	if cfg.ClientCA != "" {
+		if tlsCfg.ClientCAs, err = loadClientCAs(cfg.ClientCA); err != nil {
+			return nil, fmt.Errorf("invalid client CA: %s", err.Error())
+		}
+	}
+	if tlsCfg.MinVersion, err = cfg.Version(); err != nil {
+		return nil, err
+	}
+	if tlsCfg.CipherSuites, err = cfg.CipherSuiteIDs(); err != nil {
+		return nil, err
+	}
+	return &tlsCfg, nil
+}
+
+func listen(cfg config.Listener) (net.Listener, error) {
+	if cfg.Socket != "" {
+		if info, err := os.Stat(cfg.Socket); err == nil && info.Mode()&os.ModeSocket != 0 {
+			if err := os.Remove(cfg.Socket); err != nil {
+				return nil, err
+			}
+		}
+		return net.Listen("unix", cfg.Socket)
+	}
+	addr := cfg.Address
+	if addr == "" {
+		if cfg.TLS.Enabled {
+			addr = ":https"
+		} else {
+			addr = ":http"
+		}
+	}
+	return net.Listen("tcp", addr)
+}
+
+// open validates the TLS settings and opens the listener
+func (l *listener) open() error {
+	var tlsCfg *tls.Config
+	if l.cfg.TLS.Enabled {
+		reloader, err := newCertReloader(l.cfg.TLS.Cert, l.cfg.TLS.Key)
+		if err != nil {
+			return err
+		}
+		tlsCfg, err = newTLSConfig(l.cfg.TLS, reloader)
+		if err != nil {
+			return err
+		}
+		l.reloader = reloader
+	}
+	ln, err := listen(l.cfg)
+	if err != nil {
+		return err
+	}
+	if l.cfg.Socket != "" {
+		l.url = "unix://" + l.cfg.Socket
+	} else {
+		l.cfg.Address = ln.Addr().String()
+		if tlsCfg != nil {
+			l.url = "https://" + l.cfg.Address + "/"
+		} else {
+			l.url = "http://" + l.cfg.Address + "/"
+		}
+	}
+	if tlsCfg != nil {
+		ln = tls.NewListener(ln, tlsCfg)
+	}
+	l.ln = ln
+	return nil
+}
+
+// redirectHTTPS redirects all requests to the same host with the port of the TLS address
+func redirectHTTPS(tlsAddress string) http.Handler {
+	_, port, err := net.SplitHostPort(tlsAddress)
+	if err != nil || port == "443" || port == "https" {
+		port = ""
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		if port != "" {
+			host += ":" + port
+		}
+		u := *r.URL
+		u.Scheme = "https"
+		u.Host = host
+		http.Redirect(w, r, u.String(), http.StatusPermanentRedirect)
+	})
+}
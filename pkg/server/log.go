@@ -39,10 +39,13 @@ func LogHTTP(mapper *oas3.Mapper) mux.MiddlewareFunc {
 			defer func() {
 				duration := time.Since(start)
 				route := mux.CurrentRoute(r)
-				op := mapper.ByRoute(route)
+				var opID string
+				if op := mapper.ByRoute(route); op != nil {
+					opID = op.ID
+				}
 				log.Println(
 					"Request",
-					op.ID,
+					opID,
 					r.Host,
 					r.RemoteAddr,
 					r.Method,
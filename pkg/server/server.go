@@ -3,18 +3,22 @@ package server
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
-	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gorilla/mux"
 
 	"github.com/SVilgelm/oas3-server/pkg/config"
 	"github.com/SVilgelm/oas3-server/pkg/oas3"
+	"github.com/SVilgelm/oas3-server/pkg/tracing"
 )
 
 // Server is a OpenAPI 3 Specification Web Server
@@ -22,127 +26,456 @@ type Server struct {
 	HTTPServer *http.Server
 	Config     *config.Config
 	R          *mux.Router
-	mapper     *oas3.Mapper
+	apis       map[string]*API
+	order      []*API
+	exporter   tracing.Exporter
+	health     *health
+	hooks      []func(ctx context.Context) error
+
+	mu        sync.Mutex
+	listeners []*listener
+	done      chan struct{}
+	err       error
 }
 
-// HandleFunc links the handler with the operation
-func (s *Server) HandleFunc(operationID string, handler http.HandlerFunc) error {
-	item := s.mapper.ByID(operationID)
-	if item == nil {
-		return fmt.Errorf("the operation '%s' not found", operationID)
+// exit terminates the process when a second signal is received during the shutdown
+var exit = os.Exit
+
+// newExporter creates the exporter of the spans by the tracing output
+var newExporter = func(output string) (tracing.Exporter, error) {
+	return tracing.NewFileExporter(output)
+}
+
+// OnShutdown registers a function to call after all active requests are finished,
+// the functions are called in the reverse order of the registration
+func (s *Server) OnShutdown(hook func(ctx context.Context) error) {
+	s.hooks = append(s.hooks, hook)
+}
+
+// AddHealthCheck registers a check reported by the readiness endpoint
+func (s *Server) AddHealthCheck(name string, check func(ctx context.Context) error) {
+	s.health.add(name, check)
+}
+
+// declaresOperation checks if the model has an operation with the id
+func declaresOperation(model *openapi3.Swagger, operationID string) bool {
+	if model == nil {
+		return false
 	}
-	log.Printf("Linking new handler for the operation '%s'", operationID)
-	for _, route := range item.Routes {
-		route.HandlerFunc(handler)
+	for _, item := range model.Paths {
+		for _, op := range item.Operations() {
+			if op.OperationID == operationID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// registerProbe registers the handler at the path if the main model doesn't declare the operation,
+// it's called before the APIs are added, so the routes of an API at / don't shadow the path
+func (s *Server) registerProbe(operationID, path string, handler http.HandlerFunc) {
+	if !declaresOperation(s.Config.Model, operationID) {
+		s.R.Path(path).Methods(http.MethodGet, http.MethodHead).HandlerFunc(handler)
+	}
+}
+
+// handleProbe links the handler with the operation if the main model declares it
+func (s *Server) handleProbe(operationID string, handler http.HandlerFunc) {
+	if s.API("").mapper.ByID(operationID) != nil {
+		_ = s.HandleFunc(operationID, handler)
 	}
-	return nil
 }
 
-// Handle links the handler with the operation
+// API returns the API by its name, the empty name is the main specification of the config
+func (s *Server) API(name string) *API {
+	return s.apis[name]
+}
+
+// APIs returns all APIs ordered by their names, the main specification is the first
+func (s *Server) APIs() []*API {
+	apis := make([]*API, 0, len(s.apis))
+	for _, api := range s.apis {
+		apis = append(apis, api)
+	}
+	sort.Slice(apis, func(i, j int) bool {
+		return apis[i].Name < apis[j].Name
+	})
+	return apis
+}
+
+// Routes returns the routes of all APIs in the matching order
+func (s *Server) Routes() []oas3.RouteInfo {
+	var routes []oas3.RouteInfo
+	for _, api := range s.order {
+		_ = api.mapper.Walk(func(route oas3.RouteInfo) error {
+			route.API = api.Name
+			routes = append(routes, route)
+			return nil
+		})
+	}
+	return routes
+}
+
+// HandleFunc links the handler with the operation of the main specification
+func (s *Server) HandleFunc(operationID string, handler http.HandlerFunc) error {
+	return s.API("").HandleFunc(operationID, handler)
+}
+
+// Handle links the handler with the operation of the main specification
 func (s *Server) Handle(operationID string, handler http.Handler) error {
-	item := s.mapper.ByID(operationID)
-	if item == nil {
-		return fmt.Errorf("the operation '%s' not found", operationID)
+	return s.API("").Handle(operationID, handler)
+}
+
+// MockUnbound links the mock handlers with the operations without handlers of all APIs
+func (s *Server) MockUnbound() {
+	for _, api := range s.APIs() {
+		api.MockUnbound()
 	}
-	log.Printf("Linking new handler for the operation '%s'", operationID)
-	for _, route := range item.Routes {
-		route.Handler(handler)
+}
+
+// shutdownTimeout returns the shutdown timeout of the config or the default one if it is not set
+func (s *Server) shutdownTimeout() time.Duration {
+	if timeout := s.Config.Shutdown.Timeout.Duration(); timeout > 0 {
+		return timeout
 	}
-	return nil
+	return defaultShutdownTimeout
 }
 
-// Shutdown gracefully shutdowns the server
+// Shutdown gracefully shutdowns the server.
+// The readiness checks start failing, after the drain delay the listeners are closed,
+// then the active requests are awaited and the shutdown hooks are called.
+// All steps after the drain delay are limited by the shutdown timeout.
 func (s *Server) Shutdown() error {
-	return s.HTTPServer.Shutdown(context.Background())
+	s.health.setStopping()
+	if drain := s.Config.Shutdown.Drain.Duration(); drain > 0 {
+		log.Println("Draining service for", drain)
+		time.Sleep(drain)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout())
+	defer cancel()
+
+	err := s.shutdownServers(ctx)
+	for i := len(s.hooks) - 1; i >= 0; i-- {
+		if hErr := s.hooks[i](ctx); hErr != nil {
+			log.Println("Shutdown hook failed:", hErr.Error())
+			if err == nil {
+				err = hErr
+			}
+		}
+	}
+	if cErr := s.closeExporter(); cErr != nil && err == nil {
+		err = cErr
+	}
+	return err
 }
 
-// Start runs the server
-func (s *Server) Start() error {
-	addr := s.Config.Address
-	if addr == "" {
-		if s.Config.TLS.Enabled {
-			addr = ":https"
+func (s *Server) closeExporter() error {
+	if closer, ok := s.exporter.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (s *Server) listenerConfigs() []config.Listener {
+	if len(s.Config.Listeners) > 0 {
+		return s.Config.Listeners
+	}
+	return []config.Listener{{
+		Address: s.Config.Address,
+		TLS:     s.Config.TLS,
+	}}
+}
+
+// openListeners opens all listeners or none of them,
+// the listeners redirecting to HTTPS require a TLS listener with an address
+func (s *Server) openListeners() ([]*listener, error) {
+	cfgs := s.listenerConfigs()
+	redirect, hasTLS := "", false
+	for _, cfg := range cfgs {
+		if cfg.RedirectHTTPS && !cfg.TLS.Enabled && redirect == "" {
+			redirect = cfg.Address + cfg.Socket
+		}
+		hasTLS = hasTLS || cfg.TLS.Enabled && cfg.Socket == ""
+	}
+	if redirect != "" && !hasTLS {
+		return nil, fmt.Errorf("the listener %s redirects to HTTPS without a TLS listener", redirect)
+	}
+	var listeners []*listener
+	for _, cfg := range cfgs {
+		l := listener{cfg: cfg}
+		if err := l.open(); err != nil {
+			for _, opened := range listeners {
+				_ = opened.ln.Close()
+			}
+			return nil, err
+		}
+		listeners = append(listeners, &l)
+	}
+	tlsAddress := ""
+	for _, l := range listeners {
+		if l.cfg.TLS.Enabled && l.cfg.Socket == "" {
+			tlsAddress = l.cfg.Address
+			break
+		}
+	}
+	for _, l := range listeners {
+		if l.cfg.RedirectHTTPS && !l.cfg.TLS.Enabled {
+			l.server = &http.Server{
+				ReadTimeout:  s.HTTPServer.ReadTimeout,
+				WriteTimeout: s.HTTPServer.WriteTimeout,
+				Handler:      redirectHTTPS(tlsAddress),
+			}
 		} else {
-			addr = ":http"
+			l.server = s.HTTPServer
+		}
+	}
+	return listeners, nil
+}
+
+// shutdownServers gracefully shutdowns all HTTP servers at once,
+// the servers are forcibly closed if the context expires
+func (s *Server) shutdownServers(ctx context.Context) error {
+	servers := []*http.Server{s.HTTPServer}
+	s.mu.Lock()
+	for _, l := range s.listeners {
+		if l.server != s.HTTPServer {
+			servers = append(servers, l.server)
+		}
+		if l.reloader != nil {
+			l.reloader.close()
 		}
 	}
-	ln, err := net.Listen("tcp", addr)
+	s.mu.Unlock()
+
+	errs := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func(srv *http.Server) {
+			err := srv.Shutdown(ctx)
+			if err != nil {
+				log.Println("Cannot gracefully stop service:", err.Error())
+				if cErr := srv.Close(); cErr != nil {
+					log.Println("Cannot close service:", cErr.Error())
+				}
+			}
+			errs <- err
+		}(srv)
+	}
+	var err error
+	for range servers {
+		if sErr := <-errs; sErr != nil && err == nil {
+			err = sErr
+		}
+	}
+	return err
+}
+
+// stopServing shutdowns the other listeners after a listener failed, so Done is closed
+func (s *Server) stopServing() {
+	s.health.setStopping()
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout())
+	defer cancel()
+	_ = s.shutdownServers(ctx)
+}
+
+// Start runs the server.
+// All configuration and listening errors are returned before the server starts serving,
+// the serving result is reported by Done and Err, a failed listener stops all of them
+func (s *Server) Start() error {
+	listeners, err := s.openListeners()
 	if err != nil {
 		return err
 	}
-	s.Config.Address = ln.Addr().(*net.TCPAddr).String()
+	if len(s.Config.Listeners) == 0 {
+		s.Config.Address = listeners[0].cfg.Address
+	} else {
+		for i, l := range listeners {
+			s.Config.Listeners[i].Address = l.cfg.Address
+		}
+	}
 
-	errors := make(chan error, 1)
+	s.mu.Lock()
+	s.listeners = listeners
+	s.done = make(chan struct{})
+	s.err = nil
+	s.mu.Unlock()
 
-	go func(listener net.Listener, errors chan error) {
-		if s.Config.TLS.Enabled {
-			errors <- s.HTTPServer.ServeTLS(listener, s.Config.TLS.Cert, s.Config.TLS.Key)
-		} else {
-			errors <- s.HTTPServer.Serve(listener)
+	var wg sync.WaitGroup
+	for _, l := range listeners {
+		if l.reloader != nil {
+			go l.reloader.watch()
 		}
-		close(errors)
-	}(ln, errors)
-	time.Sleep(1 * time.Microsecond)
+		wg.Add(1)
+		go func(l *listener) {
+			defer wg.Done()
+			err := l.server.Serve(l.ln)
+			if err == http.ErrServerClosed {
+				return
+			}
+			s.mu.Lock()
+			first := s.err == nil
+			if first {
+				s.err = err
+			}
+			s.mu.Unlock()
+			if first {
+				log.Println("Service stopped listening on", l.url+":", err.Error())
+				s.stopServing()
+			}
+		}(l)
+		log.Println("Service is listening on", l.url)
+	}
+	go func(done chan struct{}) {
+		wg.Wait()
+		close(done)
+	}(s.done)
+	return nil
+}
 
-	if len(errors) > 0 {
-		return <-errors
+// Done returns a channel that is closed when the server stops serving
+func (s *Server) Done() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.done
+}
+
+// Err returns the error that stopped the server, nil for graceful shutdown or if the server is still serving
+func (s *Server) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// URL returns the URL of the first listener, available after Start
+func (s *Server) URL() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.listeners) == 0 {
+		return ""
 	}
-	var u string
-	if s.Config.TLS.Enabled {
-		u = "https://"
-	} else {
-		u = "http://"
+	return s.listeners[0].url
+}
+
+// URLs returns the URLs of all listeners, available after Start
+func (s *Server) URLs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	urls := make([]string, len(s.listeners))
+	for i, l := range s.listeners {
+		urls[i] = l.url
 	}
-	u += s.Config.Address + "/"
-	log.Println("Service is listening on", u)
-	return nil
+	return urls
 }
 
-// Serve starts Server
+// Serve starts Server and waits for SIGINT or SIGTERM to gracefully stop it,
+// the second signal terminates the process immediately
 func (s *Server) Serve() error {
-	var gracefulStop = make(chan os.Signal, 1)
+	var gracefulStop = make(chan os.Signal, 2)
 	signal.Notify(gracefulStop, syscall.SIGTERM)
 	signal.Notify(gracefulStop, syscall.SIGINT)
+	defer signal.Stop(gracefulStop)
 
 	err := s.Start()
 	if err != nil {
-		close(gracefulStop)
 		return err
 	}
 	log.Println("Please press Ctrl+C to stop service")
-	<-gracefulStop
+	select {
+	case <-gracefulStop:
+	case <-s.Done():
+		return s.Err()
+	}
 	log.Println("Gracefully stopping service")
 
-	return s.Shutdown()
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Shutdown()
+	}()
+	select {
+	case err = <-done:
+		return err
+	case sig := <-gracefulStop:
+		log.Println("Received", sig, "during shutdown, forcing exit")
+		exit(1)
+		return nil
+	}
 }
 
-// NewServer creates new server
+const (
+	// defaultTimeout limits reading and writing of the requests if the operations have shorter timeouts
+	defaultTimeout = 10 * time.Second
+	// timeoutGrace is the time to respond by the timeout error after the deadline of the operation
+	timeoutGrace = 5 * time.Second
+	// defaultShutdownTimeout limits the shutdown if the config doesn't set the timeout
+	defaultShutdownTimeout = 30 * time.Second
+)
+
+// adjustTimeouts extends the read and write timeouts of the HTTP server to the longest timeout of the operations,
+// so the connections of the long operations are not closed before the handlers complete
+func (s *Server) adjustTimeouts() {
+	var longest time.Duration
+	for _, api := range s.apis {
+		if timeout := api.mapper.MaxTimeout(); timeout > longest {
+			longest = timeout
+		}
+	}
+	if timeout := longest + timeoutGrace; longest > 0 && timeout > s.HTTPServer.WriteTimeout {
+		s.HTTPServer.ReadTimeout = timeout
+		s.HTTPServer.WriteTimeout = timeout
+	}
+}
+
+// addAPIs creates the main API and the additional ones and checks the conflicts of their routes,
+// the APIs with the longer base paths are matched first, so the APIs at the parent paths don't shadow them
+func (s *Server) addAPIs() error {
+	apiCfgs := append([]config.API{{Model: s.Config.Model, BasePath: s.Config.BasePath}}, s.Config.APIs...)
+	sort.SliceStable(apiCfgs, func(i, j int) bool {
+		return basePathDepth(apiCfgs[i]) > basePathDepth(apiCfgs[j])
+	})
+	for _, apiCfg := range apiCfgs {
+		api, err := s.newAPI(apiCfg.Name, apiCfg.Model, apiCfg.BasePath)
+		if err != nil {
+			return err
+		}
+		s.apis[apiCfg.Name] = api
+		s.order = append(s.order, api)
+	}
+	return oas3.CheckRoutes(s.Routes())
+}
+
+// NewServer creates new server, the config is checked by config.Config.Check first
 func NewServer(cfg *config.Config) (*Server, error) {
+	if err := cfg.Check(); err != nil {
+		return nil, err
+	}
 	srv := Server{
 		HTTPServer: &http.Server{
-			ReadTimeout:  10 * time.Second,
-			WriteTimeout: 10 * time.Second,
-			Handler:      mux.NewRouter(),
+			ReadHeaderTimeout: defaultTimeout,
+			ReadTimeout:       defaultTimeout,
+			WriteTimeout:      defaultTimeout,
+			Handler:           mux.NewRouter(),
 		},
 		Config: cfg,
+		apis:   make(map[string]*API),
+		health: newHealth(),
 	}
 	srv.R = srv.HTTPServer.Handler.(*mux.Router)
-	mapper, err := oas3.RegisterOperations(srv.Config.Model, srv.R)
-	if err != nil {
-		return nil, err
+	if cfg.Tracing.Enabled {
+		exporter, err := newExporter(cfg.Tracing.Output)
+		if err != nil {
+			return nil, err
+		}
+		srv.exporter = exporter
 	}
-	srv.mapper = mapper
-	srv.R.Use(LogHTTP(srv.mapper), oas3.Middleware(
-		srv.mapper,
-		srv.Config.Validate.Request,
-		srv.Config.Validate.Response,
-	))
-	_ = srv.HandleFunc("oas3.model", oas3.Model)
-	_ = srv.HandleFunc("oas3.console", oas3.Console)
-	if _, err := os.Stat(cfg.Static); !os.IsNotExist(err) {
-		fileServer := http.FileServer(FileSystem{http.Dir(cfg.Static)})
-		_ = srv.Handle("static", fileServer)
+	srv.registerProbe("oas3.health", "/healthz", srv.health.liveness)
+	srv.registerProbe("oas3.ready", "/readyz", srv.health.readiness)
+	if err := srv.addAPIs(); err != nil {
+		_ = srv.closeExporter()
+		return nil, err
 	}
+	srv.adjustTimeouts()
+	srv.handleProbe("oas3.health", srv.health.liveness)
+	srv.handleProbe("oas3.ready", srv.health.readiness)
 
 	return &srv, nil
 }
@@ -0,0 +1,722 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SVilgelm/oas3-server/pkg/config"
+	"github.com/SVilgelm/oas3-server/pkg/oas3"
+	"github.com/SVilgelm/oas3-server/pkg/tracing"
+)
+
+func TestStart(t *testing.T) {
+	t.Parallel()
+	srv, err := NewServer(&config.Config{Address: "127.0.0.1:0"})
+	assert.NoError(t, err)
+	assert.NoError(t, srv.Start())
+	assert.NotContains(t, srv.URL(), ":0/")
+	assert.Contains(t, srv.URL(), "http://127.0.0.1:")
+
+	resp, err := http.Get(srv.URL() + "healthz")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	select {
+	case <-srv.Done():
+		t.Fatal("the server is stopped before Shutdown")
+	default:
+	}
+	assert.NoError(t, srv.Shutdown())
+	<-srv.Done()
+	assert.NoError(t, srv.Err())
+}
+
+func TestStartBadTLS(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "tls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cert := filepath.Join(dir, "cert.pem")
+	key := filepath.Join(dir, "key.pem")
+	assert.NoError(t, ioutil.WriteFile(cert, []byte("bad cert"), 0600))
+	assert.NoError(t, ioutil.WriteFile(key, []byte("bad key"), 0600))
+
+	srv, err := NewServer(&config.Config{
+		Address: "127.0.0.1:0",
+		TLS: config.TLS{
+			Enabled: true,
+			Cert:    cert,
+			Key:     key,
+		},
+	})
+	assert.NoError(t, err)
+	err = srv.Start()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid TLS certificate or key")
+	assert.Empty(t, srv.URL())
+}
+
+func writeCertificate(t *testing.T, dir, name string, notAfter time.Time) (string, string) {
+	return writeSignedCertificate(t, dir, name, notAfter, "", "")
+}
+
+// writeSignedCertificate writes a certificate signed by the CA, the certificate is self-signed if the CA is empty
+func writeSignedCertificate(t *testing.T, dir, name string, notAfter time.Time, caCert, caKey string) (string, string) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	parent, parentKey := &template, interface{}(priv)
+	if caCert != "" {
+		pair, err := tls.LoadX509KeyPair(caCert, caKey)
+		assert.NoError(t, err)
+		parent, err = x509.ParseCertificate(pair.Certificate[0])
+		assert.NoError(t, err)
+		parentKey = pair.PrivateKey
+		template.IsCA = false
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, parent, &priv.PublicKey, parentKey)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(priv)
+	assert.NoError(t, err)
+
+	cert := filepath.Join(dir, name+".crt")
+	key := filepath.Join(dir, name+".key")
+	assert.NoError(t, ioutil.WriteFile(cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.NoError(t, ioutil.WriteFile(key, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return cert, key
+}
+
+func TestStartListeners(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "listeners")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cert, key := writeCertificate(t, dir, "server", time.Now().Add(time.Hour))
+	socket := filepath.Join(dir, "server.sock")
+
+	srv, err := NewServer(&config.Config{
+		Listeners: []config.Listener{
+			{Address: "127.0.0.1:0", RedirectHTTPS: true},
+			{Address: "127.0.0.1:0", TLS: config.TLS{Enabled: true, Cert: cert, Key: key}},
+			{Socket: socket},
+		},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, srv.Start())
+	defer func() {
+		assert.NoError(t, srv.Shutdown())
+		<-srv.Done()
+	}()
+	urls := srv.URLs()
+	assert.Len(t, urls, 3)
+	assert.True(t, strings.HasPrefix(urls[0], "http://"))
+	assert.True(t, strings.HasPrefix(urls[1], "https://"))
+	assert.Equal(t, "unix://"+socket, urls[2])
+
+	client := http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(urls[0] + "healthz?full=1")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusPermanentRedirect, resp.StatusCode)
+	assert.Equal(t, urls[1]+"healthz?full=1", resp.Header.Get("Location"))
+
+	client.Transport = &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	resp, err = client.Get(urls[1] + "healthz")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	client.Transport = &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}
+	resp, err = client.Get("http://unix/healthz")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestRedirectWithoutTLS(t *testing.T) {
+	t.Parallel()
+	listeners := []config.Listener{
+		{Address: "127.0.0.1:0", RedirectHTTPS: true},
+		{Address: "127.0.0.1:0"},
+	}
+	_, err := NewServer(&config.Config{Listeners: listeners})
+	assert.EqualError(t, err, "invalid config. 1 problem(s):\n"+
+		"\tlisteners[0].redirect_https: a TLS listener with an address is required to redirect to HTTPS")
+
+	srv, err := NewServer(&config.Config{})
+	assert.NoError(t, err)
+	srv.Config.Listeners = listeners
+	assert.EqualError(t, srv.Start(), "the listener 127.0.0.1:0 redirects to HTTPS without a TLS listener")
+}
+
+func TestMutualTLS(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "mtls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cert, key := writeCertificate(t, dir, "server", time.Now().Add(time.Hour))
+	clientCert, clientKey := writeCertificate(t, dir, "client", time.Now().Add(time.Hour))
+
+	model, err := oas3.Load("testdata/mtls.yaml")
+	assert.NoError(t, err)
+	srv, err := NewServer(&config.Config{
+		Address: "127.0.0.1:0",
+		TLS: config.TLS{
+			Enabled:    true,
+			Cert:       cert,
+			Key:        key,
+			ClientCA:   clientCert,
+			MinVersion: "1.2",
+		},
+		Model: model,
+	})
+	assert.NoError(t, err)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(oas3.ClientSubject(r)))
+	}
+	assert.NoError(t, srv.HandleFunc("whoami", handler))
+	assert.NoError(t, srv.HandleFunc("public", handler))
+	assert.NoError(t, srv.Start())
+	defer func() {
+		assert.NoError(t, srv.Shutdown())
+	}()
+
+	get := func(client *http.Client, path string) (int, string) {
+		resp, err := client.Get(srv.URL() + path)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	anonymous := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+	status, _ := get(anonymous, "whoami")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, body := get(anonymous, "public")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, body)
+
+	pair, err := tls.LoadX509KeyPair(clientCert, clientKey)
+	assert.NoError(t, err)
+	authenticated := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true, Certificates: []tls.Certificate{pair}},
+	}}
+	status, body = get(authenticated, "whoami")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "CN=client", body)
+}
+
+func TestCertReloader(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "reload")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cert, key := writeCertificate(t, dir, "server", time.Now().Add(time.Hour))
+
+	reloader, err := newCertReloader(cert, key)
+	assert.NoError(t, err)
+	current, err := reloader.GetCertificate(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "server", current.Leaf.Subject.CommonName)
+	assert.False(t, reloader.changed())
+
+	notAfter := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	newCert, newKey := writeCertificate(t, dir, "server", notAfter)
+	assert.Equal(t, cert, newCert)
+	future := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(newKey, future, future))
+	assert.True(t, reloader.changed())
+	assert.NoError(t, reloader.reload())
+	assert.False(t, reloader.changed())
+	current, err = reloader.GetCertificate(nil)
+	assert.NoError(t, err)
+	assert.True(t, notAfter.Equal(current.Leaf.NotAfter))
+
+	assert.NoError(t, ioutil.WriteFile(key, []byte("broken key"), 0600))
+	assert.Error(t, reloader.reload())
+	current, err = reloader.GetCertificate(nil)
+	assert.NoError(t, err)
+	assert.True(t, notAfter.Equal(current.Leaf.NotAfter))
+}
+
+func TestAPIs(t *testing.T) {
+	t.Parallel()
+	v1, err := oas3.Load("testdata/v1.yaml")
+	assert.NoError(t, err)
+	v2, err := oas3.Load("testdata/v2.yaml")
+	assert.NoError(t, err)
+	srv, err := NewServer(&config.Config{
+		Address: "127.0.0.1:0",
+		Model:   v1,
+		APIs:    []config.API{{Name: "v2", Model: v2}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, srv.HandleFunc("version", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("v1"))
+	}))
+	assert.NoError(t, srv.API("v2").HandleFunc("version", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("v2"))
+	}))
+	assert.Error(t, srv.API("v3").HandleFunc("version", nil))
+	assert.NoError(t, srv.Start())
+	defer func() {
+		assert.NoError(t, srv.Shutdown())
+	}()
+
+	get := func(path string) (int, string) {
+		req, err := http.NewRequest(http.MethodGet, srv.URL()+path, nil)
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	for _, version := range []string{"v1", "v2"} {
+		status, body := get(version + "/version")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, version, body)
+
+		status, body = get(version + "/openapi")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Contains(t, body, `"url":"http://localhost/`+version+`"`)
+	}
+	status, _ := get("healthz")
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestTimeouts(t *testing.T) {
+	t.Parallel()
+	v1, err := oas3.Load("testdata/v1.yaml")
+	assert.NoError(t, err)
+	srv, err := NewServer(&config.Config{Model: v1})
+	assert.NoError(t, err)
+	assert.Equal(t, defaultTimeout, srv.HTTPServer.WriteTimeout)
+
+	srv, err = NewServer(&config.Config{Model: v1, Timeout: config.Duration(time.Minute)})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute+timeoutGrace, srv.HTTPServer.ReadTimeout)
+	assert.Equal(t, time.Minute+timeoutGrace, srv.HTTPServer.WriteTimeout)
+	assert.Equal(t, defaultTimeout, srv.HTTPServer.ReadHeaderTimeout)
+}
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+	srv, err := NewServer(&config.Config{})
+	assert.NoError(t, err)
+	srv.AddHealthCheck("db", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+
+	get := func(path string) (int, string) {
+		rec := httptest.NewRecorder()
+		srv.R.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code, rec.Body.String()
+	}
+	status, body := get("/healthz")
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"status":"ok","checks":[]}`, body)
+	status, body = get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.JSONEq(t, `{"status":"fail","checks":[{"name":"db","status":"fail","error":"connection refused"}]}`, body)
+
+	srv.AddHealthCheck("db", func(ctx context.Context) error {
+		return nil
+	})
+	status, _ = get("/readyz")
+	assert.Equal(t, http.StatusOK, status)
+
+	srv.health.setStopping()
+	status, body = get("/healthz")
+	assert.Equal(t, http.StatusOK, status)
+	status, body = get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.JSONEq(t, `{"status":"fail","checks":[
+		{"name":"db","status":"ok"},
+		{"name":"shutdown","status":"fail","error":"the server is shutting down"}
+	]}`, body)
+}
+
+func TestHealthBeforeAPIs(t *testing.T) {
+	t.Parallel()
+	pages, err := oas3.Load("testdata/catchall.yaml")
+	assert.NoError(t, err)
+	srv, err := NewServer(&config.Config{Model: pages, BasePath: "/"})
+	assert.NoError(t, err)
+	assert.NoError(t, srv.HandleFunc("page", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("page"))
+	}))
+	srv.ServeStatic()
+
+	for path, expected := range map[string]string{
+		"/healthz": `{"status":"ok","checks":[]}`,
+		"/readyz":  `{"status":"ok","checks":[]}`,
+	} {
+		rec := httptest.NewRecorder()
+		srv.R.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+		assert.JSONEq(t, expected, rec.Body.String(), path)
+	}
+	rec := httptest.NewRecorder()
+	srv.R.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/about", nil))
+	assert.Equal(t, "page", rec.Body.String())
+}
+
+func TestShutdownHooks(t *testing.T) {
+	t.Parallel()
+	srv, err := NewServer(&config.Config{Address: "127.0.0.1:0"})
+	assert.NoError(t, err)
+	assert.Equal(t, defaultShutdownTimeout, srv.shutdownTimeout())
+
+	var calls []string
+	hook := func(name string, err error) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			deadline, ok := ctx.Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(defaultShutdownTimeout), deadline, time.Second)
+			calls = append(calls, name)
+			return err
+		}
+	}
+	srv.OnShutdown(hook("db", nil))
+	srv.OnShutdown(hook("cache", errors.New("cache failed")))
+	srv.OnShutdown(hook("queue", errors.New("queue failed")))
+	srv.OnShutdown(hook("metrics", nil))
+	assert.NoError(t, srv.Start())
+	assert.EqualError(t, srv.Shutdown(), "queue failed")
+	<-srv.Done()
+	assert.NoError(t, srv.Err())
+	assert.Equal(t, []string{"metrics", "queue", "cache", "db"}, calls)
+}
+
+func TestShutdownDrain(t *testing.T) {
+	t.Parallel()
+	srv, err := NewServer(&config.Config{
+		Address:  "127.0.0.1:0",
+		Shutdown: config.Shutdown{Drain: config.Duration(200 * time.Millisecond)},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, srv.Start())
+
+	start := time.Now()
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- srv.Shutdown()
+	}()
+	time.Sleep(50 * time.Millisecond)
+	resp, err := http.Get(srv.URL() + "readyz")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	assert.NoError(t, <-stopped)
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+	<-srv.Done()
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	t.Parallel()
+	srv, err := NewServer(&config.Config{
+		Address:  "127.0.0.1:0",
+		Shutdown: config.Shutdown{Timeout: config.Duration(100 * time.Millisecond)},
+	})
+	assert.NoError(t, err)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	srv.R.Path("/stuck").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	hookErr := make(chan error, 1)
+	srv.OnShutdown(func(ctx context.Context) error {
+		hookErr <- ctx.Err()
+		return nil
+	})
+	assert.NoError(t, srv.Start())
+	go func() {
+		if resp, err := http.Get(srv.URL() + "stuck"); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+
+	start := time.Now()
+	assert.Equal(t, context.DeadlineExceeded, srv.Shutdown())
+	assert.True(t, time.Since(start) < 5*time.Second)
+	assert.Equal(t, context.DeadlineExceeded, <-hookErr)
+	<-srv.Done()
+}
+
+func TestServeForcedExit(t *testing.T) {
+	srv, err := NewServer(&config.Config{Address: "127.0.0.1:0"})
+	assert.NoError(t, err)
+	release := make(chan struct{})
+	stopping := make(chan struct{})
+	srv.OnShutdown(func(ctx context.Context) error {
+		close(stopping)
+		<-release
+		return nil
+	})
+	exited := make(chan int, 1)
+	exit = func(code int) {
+		exited <- code
+	}
+	defer func() {
+		exit = os.Exit
+	}()
+
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve()
+	}()
+	for srv.URL() == "" {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGTERM))
+	<-stopping
+	assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGINT))
+	assert.Equal(t, 1, <-exited)
+	assert.NoError(t, <-served)
+	close(release)
+	<-srv.Done()
+}
+
+func TestStartListenerFailure(t *testing.T) {
+	t.Parallel()
+	srv, err := NewServer(&config.Config{
+		Listeners: []config.Listener{
+			{Address: "127.0.0.1:0"},
+			{Address: "127.0.0.1:0"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, srv.Start())
+	urls := srv.URLs()
+
+	srv.mu.Lock()
+	assert.NoError(t, srv.listeners[0].ln.Close())
+	srv.mu.Unlock()
+	select {
+	case <-srv.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server is still serving after a listener failed")
+	}
+	assert.Error(t, srv.Err())
+	assert.Contains(t, srv.Err().Error(), "use of closed network connection")
+	_, err = http.Get(urls[1] + "healthz")
+	assert.Error(t, err)
+}
+
+func TestMutualTLSRequire(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "mtls-require")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cert, key := writeCertificate(t, dir, "server", time.Now().Add(time.Hour))
+	ca, caKey := writeCertificate(t, dir, "ca", time.Now().Add(time.Hour))
+	clientCert, clientKey := writeSignedCertificate(t, dir, "client", time.Now().Add(time.Hour), ca, caKey)
+	strangerCert, strangerKey := writeCertificate(t, dir, "stranger", time.Now().Add(time.Hour))
+
+	model, err := oas3.Load("testdata/mtls.yaml")
+	assert.NoError(t, err)
+	srv, err := NewServer(&config.Config{
+		Address: "127.0.0.1:0",
+		TLS: config.TLS{
+			Enabled:    true,
+			Cert:       cert,
+			Key:        key,
+			ClientCA:   ca,
+			ClientAuth: "require",
+		},
+		Model: model,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, srv.HandleFunc("whoami", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(oas3.ClientSubject(r)))
+	}))
+	assert.NoError(t, srv.Start())
+	defer func() {
+		assert.NoError(t, srv.Shutdown())
+	}()
+
+	client := func(cert, key string) *http.Client {
+		tlsCfg := tls.Config{InsecureSkipVerify: true}
+		if cert != "" {
+			pair, err := tls.LoadX509KeyPair(cert, key)
+			assert.NoError(t, err)
+			tlsCfg.Certificates = []tls.Certificate{pair}
+		}
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: &tlsCfg}}
+	}
+
+	resp, err := client(clientCert, clientKey).Get(srv.URL() + "whoami")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "CN=client", string(data))
+
+	_, err = client("", "").Get(srv.URL() + "whoami")
+	assert.Error(t, err)
+	_, err = client(strangerCert, strangerKey).Get(srv.URL() + "whoami")
+	assert.Error(t, err)
+}
+
+// closingExporter tracks if the exporter is closed
+type closingExporter struct {
+	closed bool
+}
+
+func (e *closingExporter) Export(span *tracing.Span) error {
+	return nil
+}
+
+func (e *closingExporter) Close() error {
+	e.closed = true
+	return nil
+}
+
+func TestNewServerClosesExporter(t *testing.T) {
+	var exporter *closingExporter
+	newExporter = func(output string) (tracing.Exporter, error) {
+		exporter = &closingExporter{}
+		return exporter, nil
+	}
+	defer func() {
+		newExporter = func(output string) (tracing.Exporter, error) {
+			return tracing.NewFileExporter(output)
+		}
+	}()
+	v2, err := oas3.Load("testdata/v2.yaml")
+	assert.NoError(t, err)
+
+	srv, err := NewServer(&config.Config{Model: v2, Tracing: config.Tracing{Enabled: true}})
+	assert.NoError(t, err)
+	assert.False(t, exporter.closed)
+	assert.NoError(t, srv.Shutdown())
+	assert.True(t, exporter.closed)
+
+	_, err = NewServer(&config.Config{
+		Model:   v2,
+		APIs:    []config.API{{Name: "copy", Model: v2}},
+		Tracing: config.Tracing{Enabled: true},
+	})
+	assert.Error(t, err)
+	assert.True(t, exporter.closed, "the exporter is closed if the server can't be created")
+}
+
+func TestAPISpec(t *testing.T) {
+	t.Parallel()
+	model, err := oas3.Load("testdata/console.yaml")
+	assert.NoError(t, err)
+	srv, err := NewServer(&config.Config{Model: model})
+	assert.NoError(t, err)
+	spec := srv.API("").Spec()
+	assert.NotNil(t, spec)
+	assert.Same(t, spec, srv.API("").Mapper().ByID("oas3.model").Spec)
+	assert.Same(t, spec, srv.API("").Mapper().ByID("oas3.console").Spec)
+
+	model.Info.Version = "2.0.0"
+	assert.NoError(t, spec.Update(model))
+	for _, path := range []string{"/docs/openapi", "/docs/console"} {
+		rec := httptest.NewRecorder()
+		srv.R.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+		assert.Contains(t, rec.Body.String(), `"version":"2.0.0"`, path)
+	}
+
+	root, err := oas3.Load("testdata/root.yaml")
+	assert.NoError(t, err)
+	srv, err = NewServer(&config.Config{Model: root})
+	assert.NoError(t, err)
+	assert.Nil(t, srv.API("").Spec())
+}
+
+func TestAPIsOrder(t *testing.T) {
+	t.Parallel()
+	root, err := oas3.Load("testdata/root.yaml")
+	assert.NoError(t, err)
+	v2, err := oas3.Load("testdata/v2.yaml")
+	assert.NoError(t, err)
+	srv, err := NewServer(&config.Config{
+		Model: root,
+		APIs:  []config.API{{Name: "v2", Model: v2}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, srv.HandleFunc("version", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("root"))
+	}))
+	assert.NoError(t, srv.API("v2").HandleFunc("version", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("v2"))
+	}))
+	for path, expected := range map[string]string{"/v1/version": "root", "/v2/version": "v2"} {
+		rec := httptest.NewRecorder()
+		srv.R.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, expected, rec.Body.String(), path)
+	}
+
+	_, err = NewServer(&config.Config{
+		Model: v2,
+		APIs:  []config.API{{Name: "copy", Model: v2}},
+	})
+	assert.EqualError(t, err, "unreachable routes: "+
+		"GET /v2/openapi (oas3.model) shadows GET /v2/openapi (oas3.model) of the API 'copy'; "+
+		"GET /v2/version (version) shadows GET /v2/version (version) of the API 'copy'")
+
+	_, err = NewServer(&config.Config{
+		Model: root,
+		APIs:  []config.API{{Model: v2}, {Name: "v3", Model: v2}, {Name: "v3", Model: v2}},
+	})
+	assert.EqualError(t, err, "invalid config. 2 problem(s):\n\tapis[0]: name is required\n\tapis[2].name: duplicated name 'v3'")
+}
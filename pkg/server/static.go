@@ -38,3 +38,36 @@ func (fs FileSystem) Open(path string) (http.File, error) {
 
 	return f, nil
 }
+
+// indexedDir opens the directories with the index files only, so http.FileServer serves the indexes
+// and never lists the directories
+type indexedDir struct {
+	dir http.Dir
+}
+
+func (d indexedDir) Open(name string) (http.File, error) {
+	f, err := d.dir.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	s, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if s.IsDir() {
+		index, err := d.dir.Open(strings.TrimSuffix(name, "/") + "/index.html")
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		_ = index.Close()
+	}
+	return f, nil
+}
+
+// ServeStatic serves the static directory of the config for the requests not matched by the operations,
+// it is used if the model doesn't declare the 'static' operation
+func (s *Server) ServeStatic() {
+	s.R.PathPrefix("/").Handler(http.FileServer(indexedDir{http.Dir(s.Config.Static)}))
+}
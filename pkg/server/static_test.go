@@ -0,0 +1,53 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SVilgelm/oas3-server/pkg/config"
+)
+
+func TestFileSystem(t *testing.T) {
+	t.Parallel()
+	fs := FileSystem{http.Dir("testdata/static")}
+	f, err := fs.Open("/docs")
+	assert.NoError(t, err)
+	data, err := ioutil.ReadAll(f)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	assert.Equal(t, "<h1>docs</h1>\n", string(data), "the directory is opened as its index file")
+
+	_, err = fs.Open("/private")
+	assert.Error(t, err)
+}
+
+func TestServeStatic(t *testing.T) {
+	t.Parallel()
+	srv, err := NewServer(&config.Config{Static: "testdata/static"})
+	assert.NoError(t, err)
+	srv.ServeStatic()
+	for _, tc := range []struct {
+		path     string
+		status   int
+		location string
+		body     string
+	}{
+		{"/", http.StatusOK, "", "<h1>index</h1>\n"},
+		{"/docs/", http.StatusOK, "", "<h1>docs</h1>\n"},
+		{"/docs", http.StatusMovedPermanently, "docs/", ""},
+		{"/private/", http.StatusNotFound, "", ""},
+		{"/private/notes.txt", http.StatusOK, "", "secret\n"},
+	} {
+		rec := httptest.NewRecorder()
+		srv.R.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		assert.Equal(t, tc.status, rec.Code, tc.path)
+		assert.Equal(t, tc.location, rec.Header().Get("Location"), tc.path)
+		if tc.body != "" {
+			assert.Equal(t, tc.body, rec.Body.String(), tc.path)
+		}
+	}
+}
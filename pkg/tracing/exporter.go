@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// Exporter sends finished spans to a tracing backend
+type Exporter interface {
+	Export(span *Span) error
+}
+
+// WriterExporter writes spans as JSON lines
+type WriterExporter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// Export writes the span as a JSON line
+func (e *WriterExporter) Export(span *Span) error {
+	span.mu.Lock()
+	data, err := json.Marshal(span)
+	span.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err = e.w.Write(data)
+	return err
+}
+
+// Close closes the underlying file
+func (e *WriterExporter) Close() error {
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
+
+// NewWriterExporter creates an Exporter writing to w
+func NewWriterExporter(w io.Writer) *WriterExporter {
+	return &WriterExporter{w: w}
+}
+
+// NewFileExporter creates an Exporter appending to the file,
+// the special names "stdout" and "stderr" are supported
+func NewFileExporter(fileName string) (*WriterExporter, error) {
+	switch fileName {
+	case "", "stdout":
+		return NewWriterExporter(os.Stdout), nil
+	case "stderr":
+		return NewWriterExporter(os.Stderr), nil
+	}
+	f, err := os.OpenFile(fileName, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &WriterExporter{w: f, closer: f}, nil
+}
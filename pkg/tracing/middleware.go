@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/SVilgelm/oas3-server/pkg/oas3"
+)
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func spanName(r *http.Request, item *oas3.Item) string {
+	if item != nil {
+		return item.ID
+	}
+	return "HTTP " + r.Method
+}
+
+func finishSpan(span *Span, r *http.Request, route *mux.Route, sw *statusWriter) {
+	span.SetAttribute("http.method", r.Method)
+	span.SetAttribute("http.target", r.RequestURI)
+	span.SetAttribute("http.host", r.Host)
+	if route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			span.SetAttribute("http.route", tpl)
+		}
+	}
+	status := sw.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	span.SetAttribute("http.status_code", status)
+	if errs := oas3.ValidationErrorsFromContext(r.Context()); len(errs) > 0 {
+		messages := make([]string, len(errs))
+		for i, err := range errs {
+			messages[i] = err.Error()
+		}
+		span.SetAttribute("oas3.validation_errors", messages)
+	}
+	span.Finish()
+}
+
+// Middleware starts a span per request named after the operationId,
+// the span continues the trace of the traceparent header if any
+func Middleware(mapper *oas3.Mapper, exporter Exporter) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := mux.CurrentRoute(r)
+			item := mapper.ByRoute(route)
+			parent, _ := Extract(r.Header)
+			span := StartSpan(spanName(r, item), parent)
+			if item != nil {
+				span.SetAttribute("oas3.operation_id", item.ID)
+			}
+
+			ctx := WithSpan(r.Context(), span)
+			ctx = oas3.WithValidationErrors(ctx)
+			r = r.WithContext(ctx)
+			sw := statusWriter{ResponseWriter: w}
+			defer func() {
+				finishSpan(span, r, route, &sw)
+				if !span.Sampled {
+					return
+				}
+				if err := exporter.Export(span); err != nil {
+					log.Println("Cannot export a span:", err.Error())
+				}
+			}()
+			next.ServeHTTP(&sw, r)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type contextKey int
+
+const (
+	spanKey contextKey = iota
+)
+
+// Span describes a single traced operation
+type Span struct {
+	Name       string                 `json:"name"`
+	TraceID    TraceID                `json:"trace_id"`
+	SpanID     SpanID                 `json:"span_id"`
+	ParentID   *SpanID                `json:"parent_id,omitempty"`
+	Sampled    bool                   `json:"sampled"`
+	Start      time.Time              `json:"start"`
+	End        time.Time              `json:"end"`
+	Duration   time.Duration          `json:"duration"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+
+	mu sync.Mutex
+}
+
+// StartSpan creates a new span, the span becomes a child of the parent if the parent is valid
+func StartSpan(name string, parent SpanContext) *Span {
+	span := Span{
+		Name:       name,
+		SpanID:     newSpanID(),
+		Sampled:    true,
+		Start:      time.Now(),
+		Attributes: make(map[string]interface{}),
+	}
+	if parent.IsValid() {
+		span.TraceID = parent.TraceID
+		span.ParentID = &parent.SpanID
+		span.Sampled = parent.Sampled
+	} else {
+		span.TraceID = newTraceID()
+	}
+	return &span
+}
+
+// Context returns the span context to propagate
+func (s *Span) Context() SpanContext {
+	return SpanContext{
+		TraceID: s.TraceID,
+		SpanID:  s.SpanID,
+		Sampled: s.Sampled,
+	}
+}
+
+// SetAttribute sets an attribute of the span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Attributes[key] = value
+}
+
+// Finish sets the end time of the span
+func (s *Span) Finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.End = time.Now()
+	s.Duration = s.End.Sub(s.Start)
+}
+
+// WithSpan puts the span into the current context
+func WithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey, span)
+}
+
+// SpanFromContext returns the current span from the context or nil
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey).(*Span)
+	return span
+}
+
+// Inject sets the traceparent header of the current span,
+// used to propagate the trace to outgoing requests
+func Inject(ctx context.Context, header http.Header) {
+	span := SpanFromContext(ctx)
+	if span == nil {
+		return
+	}
+	header.Set(TraceParentHeader, span.Context().TraceParent())
+}
+
+// Transport is a http.RoundTripper injecting the traceparent header of the request context
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// RoundTrip executes a single HTTP transaction with the injected traceparent header
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if span := SpanFromContext(r.Context()); span != nil {
+		r = r.Clone(r.Context())
+		Inject(r.Context(), r.Header)
+	}
+	return base.RoundTrip(r)
+}
@@ -0,0 +1,140 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TraceParentHeader is the W3C Trace Context header
+const TraceParentHeader = "traceparent"
+
+const (
+	traceParentVersion = "00"
+	flagSampled        = 0x01
+)
+
+// TraceID is a W3C trace identifier
+type TraceID [16]byte
+
+// String returns the hex representation of the trace id
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// MarshalText encodes the trace id as hex
+func (t TraceID) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// IsValid checks that the trace id is not all zeros
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// SpanID is a W3C span (parent) identifier
+type SpanID [8]byte
+
+// String returns the hex representation of the span id
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// MarshalText encodes the span id as hex
+func (s SpanID) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// IsValid checks that the span id is not all zeros
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// SpanContext is the part of a span propagated between services
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid checks that both trace id and span id are set
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// TraceParent returns the value of the traceparent header for the span context
+func (sc SpanContext) TraceParent() string {
+	flags := byte(0)
+	if sc.Sampled {
+		flags |= flagSampled
+	}
+	return fmt.Sprintf("%s-%s-%s-%02x", traceParentVersion, sc.TraceID, sc.SpanID, flags)
+}
+
+func decodeHex(dst []byte, src string) error {
+	if len(src) != 2*len(dst) || strings.ToLower(src) != src {
+		return errors.New("invalid length or case")
+	}
+	_, err := hex.Decode(dst, []byte(src))
+	return err
+}
+
+// ParseTraceParent parses the value of the traceparent header
+func ParseTraceParent(value string) (SpanContext, error) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return sc, fmt.Errorf("invalid traceparent '%s'", value)
+	}
+	var version [1]byte
+	if err := decodeHex(version[:], parts[0]); err != nil || version[0] == 0xff {
+		return sc, fmt.Errorf("invalid traceparent version '%s'", parts[0])
+	}
+	if version[0] == 0 && len(parts) != 4 {
+		return sc, fmt.Errorf("invalid traceparent '%s'", value)
+	}
+	if err := decodeHex(sc.TraceID[:], parts[1]); err != nil || !sc.TraceID.IsValid() {
+		return sc, fmt.Errorf("invalid trace id '%s'", parts[1])
+	}
+	if err := decodeHex(sc.SpanID[:], parts[2]); err != nil || !sc.SpanID.IsValid() {
+		return sc, fmt.Errorf("invalid parent id '%s'", parts[2])
+	}
+	var flags [1]byte
+	if err := decodeHex(flags[:], parts[3]); err != nil {
+		return sc, fmt.Errorf("invalid trace flags '%s'", parts[3])
+	}
+	sc.Sampled = flags[0]&flagSampled != 0
+	return sc, nil
+}
+
+// Extract returns the span context propagated by the traceparent header
+func Extract(header http.Header) (SpanContext, bool) {
+	value := header.Get(TraceParentHeader)
+	if value == "" {
+		return SpanContext{}, false
+	}
+	sc, err := ParseTraceParent(value)
+	if err != nil {
+		return SpanContext{}, false
+	}
+	return sc, true
+}
+
+func newTraceID() TraceID {
+	var t TraceID
+	for !t.IsValid() {
+		_, _ = rand.Read(t[:])
+	}
+	return t
+}
+
+func newSpanID() SpanID {
+	var s SpanID
+	for !s.IsValid() {
+		_, _ = rand.Read(s[:])
+	}
+	return s
+}
@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SVilgelm/oas3-server/pkg/oas3"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	t.Parallel()
+	value := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	sc, err := ParseTraceParent(value)
+	assert.NoError(t, err)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", sc.TraceID.String())
+	assert.Equal(t, "b7ad6b7169203331", sc.SpanID.String())
+	assert.True(t, sc.Sampled)
+	assert.Equal(t, value, sc.TraceParent())
+
+	for _, bad := range []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+		"00-0AF7651916CD43DD8448EB211C80319C-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra",
+	} {
+		_, err = ParseTraceParent(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+	model := &openapi3.Swagger{}
+	model.AddOperation("/items/{id}", http.MethodGet, &openapi3.Operation{OperationID: "items.get"})
+	router := mux.NewRouter()
+	mapper, err := oas3.RegisterOperations(model, router)
+	assert.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	router.Use(Middleware(mapper, NewWriterExporter(buf)))
+	injected := make(http.Header)
+	for _, route := range mapper.ByID("items.get").Routes {
+		route.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Inject(r.Context(), injected)
+			w.WriteHeader(http.StatusAccepted)
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Header.Set(TraceParentHeader, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var span map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &span))
+	assert.Equal(t, "items.get", span["name"])
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", span["trace_id"])
+	assert.Equal(t, "b7ad6b7169203331", span["parent_id"])
+	attrs := span["attributes"].(map[string]interface{})
+	assert.Equal(t, "GET", attrs["http.method"])
+	assert.Equal(t, "/items/{id}", attrs["http.route"])
+	assert.Equal(t, float64(http.StatusAccepted), attrs["http.status_code"])
+
+	sc, ok := Extract(injected)
+	assert.True(t, ok)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", sc.TraceID.String())
+	assert.Equal(t, span["span_id"], sc.SpanID.String())
+}